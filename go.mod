@@ -1,8 +1,9 @@
 module github.com/superturkey650/go-qbittorrent
 
-go 1.14
+go 1.18
 
 require (
 	github.com/davecgh/go-spew v1.1.1
 	golang.org/x/net v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
 )
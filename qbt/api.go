@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/net/publicsuffix"
 )
@@ -38,60 +39,109 @@ func delimit(items []string, delimiter string) (delimited string) {
 
 // Client creates a connection to qbittorrent and performs requests
 type Client struct {
+	// session counts the renewals of the session by reauthenticate. It is accessed
+	// atomically and comes first to be 64-bit aligned on 32-bit platforms
+	session uint64
+
 	http          *http.Client
 	URL           string
 	Authenticated bool
 	Jar           http.CookieJar
+
+	// Credentials are used to log in again when the session expires.
+	// Login sets them to the credentials it was called with if they are nil
+	Credentials CredentialProvider
+	authMu      sync.Mutex
+
+	// RetryPolicy decides which failed requests are retried, nil disables retries
+	RetryPolicy *RetryPolicy
+
+	versionMu  sync.Mutex
+	apiVersion *APIVersion
+
+	userAgent   string
+	headers     http.Header
+	sessionFile string
+	err         error
 }
 
-// NewClient creates a new client connection to qbittorrent
-func NewClient(url string) *Client {
-	c := &Client{}
+// NewClient creates a new client connection to qbittorrent.
+// url is either an http(s) url or unix:///path/to/socket for a unix domain socket
+func NewClient(url string, opts ...Option) *Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &Client{
+		Credentials: o.credentials,
+		RetryPolicy: o.retryPolicy,
+		userAgent:   o.userAgent,
+		headers:     o.headers,
+		sessionFile: o.sessionFile,
+	}
+
+	// unix:///path/to/socket urls are sent over the socket with a placeholder host
+	url, c.err = o.unixSocketURL(url)
 
 	// ensure url ends with "/"
 	if !strings.HasSuffix(url, "/") {
 		url += "/"
 	}
 
-	c.URL = url
+	c.URL = joinBasePath(url, o.basePath)
+
+	// copy the user's http client so we never modify it
+	c.http = &http.Client{}
+	if o.httpClient != nil {
+		*c.http = *o.httpClient
+	}
+	if o.transport != nil {
+		c.http.Transport = o.transport
+	}
+	if o.timeout > 0 {
+		c.http.Timeout = o.timeout
+	}
+
+	// errors in the options are reported by every request made with the client
+	if c.err == nil {
+		c.http.Transport, c.err = o.configureTransport(c.http.Transport)
+	}
 
-	// create cookie jar
-	c.Jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
-	c.http = &http.Client{
-		Jar: c.Jar,
+	// create cookie jar unless the http client brought its own
+	if c.http.Jar == nil {
+		c.http.Jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 	}
+	c.Jar = c.http.Jar
+
 	return c
 }
 
 // get will perform a GET request with no parameters
-func (c *Client) get(endpoint string, opts map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", c.URL+endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build request: %w", err)
-	}
+func (c *Client) get(ctx context.Context, endpoint string, opts map[string]string) (*http.Response, error) {
+	return c.do(ctx, endpoint, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	// add user-agent header to allow qbittorrent to identify us
-	req.Header.Set("User-Agent", "go-qbittorrent v0.1")
+		c.setHeaders(req)
 
-	// add optional parameters that the user wants
-	if opts != nil {
-		query := req.URL.Query()
-		for k, v := range opts {
-			query.Add(k, v)
+		// add optional parameters that the user wants
+		if opts != nil {
+			query := req.URL.Query()
+			for k, v := range opts {
+				query.Add(k, v)
+			}
+			req.URL.RawQuery = query.Encode()
 		}
-		req.URL.RawQuery = query.Encode()
-	}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
-	}
-
-	return resp, nil
+		return req, nil
+	})
 }
 
 // post will perform a POST request with no content-type specified
-func (c *Client) post(endpoint string, opts map[string]string) (*http.Response, error) {
+func (c *Client) post(ctx context.Context, endpoint string, opts map[string]string) (*http.Response, error) {
 
 	// add optional parameters that the user wants
 	form := url.Values{}
@@ -99,50 +149,41 @@ func (c *Client) post(endpoint string, opts map[string]string) (*http.Response,
 		form.Set(k, v)
 	}
 
-	req, err := http.NewRequestWithContext(
-		context.Background(),
-		http.MethodPost,
-		c.URL+endpoint,
-		strings.NewReader(form.Encode()),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build request: %w", err)
-	}
-
-	// add the content-type so qbittorrent knows what to expect
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	// add user-agent header to allow qbittorrent to identify us
-	req.Header.Set("User-Agent", "go-qbittorrent v0.2")
-	// add referer header to allow qbittorrent to identify us
-	req.Header.Set("Referer", c.URL)
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
-	}
+	return c.do(ctx, endpoint, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodPost,
+			c.URL+endpoint,
+			strings.NewReader(form.Encode()),
+		)
+		if err != nil {
+			return nil, err
+		}
 
-	return resp, nil
+		// add the content-type so qbittorrent knows what to expect
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		c.setHeaders(req)
+		// add referer header to allow qbittorrent to identify us
+		req.Header.Set("Referer", c.URL)
 
+		return req, nil
+	})
 }
 
 // postMultipart will perform a multiple part POST request
-func (c *Client) postMultipart(endpoint string, buffer bytes.Buffer, contentType string) (resp *http.Response, err error) {
-	req, err := http.NewRequest("POST", c.URL+endpoint, &buffer)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	// add the content-type so qbittorrent knows what to expect
-	req.Header.Set("Content-Type", contentType)
-	// add user-agent header to allow qbittorrent to identify us
-	req.Header.Set("User-Agent", "go-qbittorrent v0.2")
+func (c *Client) postMultipart(ctx context.Context, endpoint string, buffer bytes.Buffer, contentType string) (resp *http.Response, err error) {
+	return c.do(ctx, endpoint, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+endpoint, bytes.NewReader(buffer.Bytes()))
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err = c.http.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
-	}
+		// add the content-type so qbittorrent knows what to expect
+		req.Header.Set("Content-Type", contentType)
+		c.setHeaders(req)
 
-	return resp, nil
+		return req, nil
+	})
 }
 
 // writeOptions will write a map to the buffer through multipart.NewWriter
@@ -156,7 +197,7 @@ func writeOptions(writer *multipart.Writer, opts map[string]string) (err error)
 }
 
 // postMultipartData will perform a multiple part POST request without a file
-func (c *Client) postMultipartData(endpoint string, opts map[string]string) (*http.Response, error) {
+func (c *Client) postMultipartData(ctx context.Context, endpoint string, opts map[string]string) (*http.Response, error) {
 	var buffer bytes.Buffer
 	writer := multipart.NewWriter(&buffer)
 
@@ -171,7 +212,7 @@ func (c *Client) postMultipartData(endpoint string, opts map[string]string) (*ht
 		return nil, fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	resp, err := c.postMultipart(endpoint, buffer, writer.FormDataContentType())
+	resp, err := c.postMultipart(ctx, endpoint, buffer, writer.FormDataContentType())
 	if err != nil {
 		return nil, err
 	}
@@ -180,7 +221,7 @@ func (c *Client) postMultipartData(endpoint string, opts map[string]string) (*ht
 }
 
 // postMultipartFile will perform a multiple part POST request with a file
-func (c *Client) postMultipartFile(endpoint string, fileName string, opts map[string]string) (*http.Response, error) {
+func (c *Client) postMultipartFile(ctx context.Context, endpoint string, fileName string, opts map[string]string) (*http.Response, error) {
 	var buffer bytes.Buffer
 	writer := multipart.NewWriter(&buffer)
 
@@ -212,7 +253,7 @@ func (c *Client) postMultipartFile(endpoint string, fileName string, opts map[st
 		return nil, fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	resp, err := c.postMultipart(endpoint, buffer, writer.FormDataContentType())
+	resp, err := c.postMultipart(ctx, endpoint, buffer, writer.FormDataContentType())
 	if err != nil {
 		return nil, err
 	}
@@ -225,45 +266,86 @@ func (c *Client) postMultipartFile(endpoint string, fileName string, opts map[st
 // Login authenticates with the qBittorrent client using the provided credentials.
 // It returns an error if authentication fails or if the client's IP is banned.
 func (c *Client) Login(username string, password string) (err error) {
+	return c.LoginCtx(context.Background(), username, password)
+}
+
+// LoginCtx is Login with a context that controls the lifetime of the request
+func (c *Client) LoginCtx(ctx context.Context, username string, password string) (err error) {
 	params := map[string]string{"username": username, "password": password}
 
 	if c.http == nil {
 		c.http = &http.Client{Jar: c.Jar}
 	}
 
-	resp, err := c.post(apiBase+"auth/login", params)
+	// reuse the saved session while qbittorrent still accepts it. A session file that
+	// cannot be restored, e.g. because it is corrupt, is replaced by a new session
+	if c.sessionFile != "" {
+		if restored, err := c.RestoreSession(ctx, c.sessionFile); err == nil && restored {
+			c.rememberCredentials(username, password)
+			return nil
+		}
+	}
+
+	resp, err := c.post(ctx, apiBase+"auth/login", params)
 	if err != nil {
-		return err
-	} else if resp.StatusCode == http.StatusForbidden {
-		return fmt.Errorf("user's IP is banned for too many failed login attempts: %w", err)
+		return describe(err, statusMessages{
+			http.StatusForbidden: "user's IP is banned for too many failed login attempts",
+		})
+	}
+	defer resp.Body.Close()
+
+	// qbittorrent answers bad credentials with a 200 and a "Fails." body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("invalid username or password: %w", ErrUnauthorized)
 	}
 
 	// change authentication status so we know were authenticated in later requests
 	c.Authenticated = true
+	c.rememberCredentials(username, password)
 
+	if c.sessionFile != "" {
+		return c.SaveSession(c.sessionFile)
+	}
 	return nil
 }
 
 // Logout logs you out of the qbittorrent client
 // returns the current authentication status
 func (c *Client) Logout() (err error) {
-	resp, err := c.get(apiBase+"auth/logout", nil)
+	return c.LogoutCtx(context.Background())
+}
+
+// LogoutCtx is Logout with a context that controls the lifetime of the request
+func (c *Client) LogoutCtx(ctx context.Context) (err error) {
+	resp, err := c.post(ctx, apiBase+"auth/logout", nil)
 	if err != nil {
 		return err
 	}
 
 	// change authentication status so we know were not authenticated in later requests
-	c.Authenticated = (*resp).StatusCode == 200
+	c.Authenticated = false
 
-	return nil
+	resp.Body.Close()
+	return c.removeSession()
 }
 
 // ApplicationVersion of the qbittorrent client
 func (c *Client) ApplicationVersion() (version string, err error) {
-	resp, err := c.get(apiBase+"app/version", nil)
+	return c.ApplicationVersionCtx(context.Background())
+}
+
+// ApplicationVersionCtx is ApplicationVersion with a context that controls the lifetime of the request
+func (c *Client) ApplicationVersionCtx(ctx context.Context) (version string, err error) {
+	resp, err := c.get(ctx, apiBase+"app/version", nil)
 	if err != nil {
 		return version, err
 	}
+	defer resp.Body.Close()
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return version, fmt.Errorf("failed to read response body: %w", err)
@@ -273,10 +355,17 @@ func (c *Client) ApplicationVersion() (version string, err error) {
 
 // WebAPIVersion of the qbittorrent client
 func (c *Client) WebAPIVersion() (version string, err error) {
-	resp, err := c.get(apiBase+"app/webapiVersion", nil)
+	return c.WebAPIVersionCtx(context.Background())
+}
+
+// WebAPIVersionCtx is WebAPIVersion with a context that controls the lifetime of the request
+func (c *Client) WebAPIVersionCtx(ctx context.Context) (version string, err error) {
+	resp, err := c.get(ctx, apiBase+"app/webapiVersion", nil)
 	if err != nil {
 		return version, err
 	}
+	defer resp.Body.Close()
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return version, fmt.Errorf("failed to read response body: %w", err)
@@ -287,10 +376,17 @@ func (c *Client) WebAPIVersion() (version string, err error) {
 
 // BuildInfo of the qbittorrent client
 func (c *Client) BuildInfo() (buildInfo BuildInfo, err error) {
-	resp, err := c.get(apiBase+"app/buildInfo", nil)
+	return c.BuildInfoCtx(context.Background())
+}
+
+// BuildInfoCtx is BuildInfo with a context that controls the lifetime of the request
+func (c *Client) BuildInfoCtx(ctx context.Context) (buildInfo BuildInfo, err error) {
+	resp, err := c.get(ctx, apiBase+"app/buildInfo", nil)
 	if err != nil {
 		return buildInfo, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&buildInfo); err != nil {
 		return buildInfo, err
 	}
@@ -299,28 +395,84 @@ func (c *Client) BuildInfo() (buildInfo BuildInfo, err error) {
 
 // Preferences of the qbittorrent client
 func (c *Client) Preferences() (prefs Preferences, err error) {
-	resp, err := c.get(apiBase+"app/preferences", nil)
+	return c.PreferencesCtx(context.Background())
+}
+
+// PreferencesCtx is Preferences with a context that controls the lifetime of the request
+func (c *Client) PreferencesCtx(ctx context.Context) (prefs Preferences, err error) {
+	resp, err := c.get(ctx, apiBase+"app/preferences", nil)
 	if err != nil {
 		return prefs, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&prefs); err != nil {
 		return prefs, err
 	}
 	return prefs, err
 }
 
-// SetPreferences of the qbittorrent client
-func (c *Client) SetPreferences() (prefsSet bool, err error) {
-	resp, err := c.post(apiBase+"app/setPreferences", nil)
-	return (resp.StatusCode == http.StatusOK), err
+// RawPreferences returns the app/preferences response as qbittorrent sent it, so the
+// preferences a release does not report can be told apart from those at their zero value
+func (c *Client) RawPreferences() (data json.RawMessage, err error) {
+	return c.RawPreferencesCtx(context.Background())
+}
+
+// RawPreferencesCtx is RawPreferences with a context that controls the lifetime of the request
+func (c *Client) RawPreferencesCtx(ctx context.Context) (data json.RawMessage, err error) {
+	resp, err := c.get(ctx, apiBase+"app/preferences", nil)
+	if err != nil {
+		return data, err
+	}
+	defer resp.Body.Close()
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return data, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return data, nil
+}
+
+// SetPreferences of the qbittorrent client. Only the preferences in the patch are changed
+func (c *Client) SetPreferences(patch PreferencesPatch) (prefsSet bool, err error) {
+	return c.SetPreferencesCtx(context.Background(), patch)
+}
+
+// SetPreferencesCtx is SetPreferences with a context that controls the lifetime of the request
+func (c *Client) SetPreferencesCtx(ctx context.Context, patch PreferencesPatch) (prefsSet bool, err error) {
+	if len(patch) == 0 {
+		return true, nil
+	}
+	if err := patch.Validate(); err != nil {
+		return false, err
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	resp, err := c.post(ctx, apiBase+"app/setPreferences", map[string]string{"json": string(data)})
+	if err != nil {
+		return false, describe(err, statusMessages{
+			http.StatusBadRequest: "invalid preferences",
+		})
+	}
+	return true, resp.Body.Close()
 }
 
 // DefaultSavePath of the qbittorrent client
 func (c *Client) DefaultSavePath() (path string, err error) {
-	resp, err := c.get(apiBase+"app/defaultSavePath", nil)
+	return c.DefaultSavePathCtx(context.Background())
+}
+
+// DefaultSavePathCtx is DefaultSavePath with a context that controls the lifetime of the request
+func (c *Client) DefaultSavePathCtx(ctx context.Context) (path string, err error) {
+	resp, err := c.get(ctx, apiBase+"app/defaultSavePath", nil)
 	if err != nil {
 		return path, err
 	}
+	defer resp.Body.Close()
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return path, fmt.Errorf("failed to read response body: %w", err)
@@ -331,18 +483,33 @@ func (c *Client) DefaultSavePath() (path string, err error) {
 
 // Shutdown shuts down the qbittorrent client
 func (c *Client) Shutdown() error {
-	_, err := c.post(apiBase+"app/shutdown", nil)
-	return err
+	return c.ShutdownCtx(context.Background())
+}
+
+// ShutdownCtx is Shutdown with a context that controls the lifetime of the request
+func (c *Client) ShutdownCtx(ctx context.Context) error {
+	resp, err := c.post(ctx, apiBase+"app/shutdown", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // Log Endpoints
 
 // Logs of the qbittorrent client
 func (c *Client) Logs(filters map[string]string) (logs []Log, err error) {
-	resp, err := c.get(apiBase+"log/main", filters)
+	return c.LogsCtx(context.Background(), filters)
+}
+
+// LogsCtx is Logs with a context that controls the lifetime of the request
+func (c *Client) LogsCtx(ctx context.Context, filters map[string]string) (logs []Log, err error) {
+	resp, err := c.get(ctx, apiBase+"log/main", filters)
 	if err != nil {
 		return logs, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&logs); err != nil {
 		return logs, err
 	}
@@ -351,10 +518,17 @@ func (c *Client) Logs(filters map[string]string) (logs []Log, err error) {
 
 // PeerLogs of the qbittorrent client
 func (c *Client) PeerLogs(filters map[string]string) (logs []PeerLog, err error) {
-	resp, err := c.get(apiBase+"log/peers", filters)
+	return c.PeerLogsCtx(context.Background(), filters)
+}
+
+// PeerLogsCtx is PeerLogs with a context that controls the lifetime of the request
+func (c *Client) PeerLogsCtx(ctx context.Context, filters map[string]string) (logs []PeerLog, err error) {
+	resp, err := c.get(ctx, apiBase+"log/peers", filters)
 	if err != nil {
 		return logs, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&logs); err != nil {
 		return logs, err
 	}
@@ -365,40 +539,107 @@ func (c *Client) PeerLogs(filters map[string]string) (logs []PeerLog, err error)
 
 // MainData returns info you usually see in qBt status bar.
 func (c *Client) MainData(rid string) (mainData MainData, err error) {
+	return c.MainDataCtx(context.Background(), rid)
+}
+
+// MainDataCtx is MainData with a context that controls the lifetime of the request
+func (c *Client) MainDataCtx(ctx context.Context, rid string) (mainData MainData, err error) {
 	params := map[string]string{"rid": rid}
-	resp, err := c.get(apiBase+"sync/maindata", params)
+	resp, err := c.get(ctx, apiBase+"sync/maindata", params)
 	if err != nil {
 		return mainData, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&mainData); err != nil {
 		return mainData, err
 	}
 	return mainData, err
 }
 
+// RawMainData returns the sync/maindata response as qbittorrent sent it, so partial
+// updates can be told apart from fields at their zero value
+func (c *Client) RawMainData(rid string) (data json.RawMessage, err error) {
+	return c.RawMainDataCtx(context.Background(), rid)
+}
+
+// RawMainDataCtx is RawMainData with a context that controls the lifetime of the request
+func (c *Client) RawMainDataCtx(ctx context.Context, rid string) (data json.RawMessage, err error) {
+	params := map[string]string{"rid": rid}
+	resp, err := c.get(ctx, apiBase+"sync/maindata", params)
+	if err != nil {
+		return data, err
+	}
+	defer resp.Body.Close()
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return data, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return data, nil
+}
+
 // TorrentPeers returns info you usually see in qBt status bar.
 func (c *Client) TorrentPeers(hash string, rid string) (torrentPeers TorrentPeers, err error) {
+	return c.TorrentPeersCtx(context.Background(), hash, rid)
+}
+
+// TorrentPeersCtx is TorrentPeers with a context that controls the lifetime of the request
+func (c *Client) TorrentPeersCtx(ctx context.Context, hash string, rid string) (torrentPeers TorrentPeers, err error) {
 	params := map[string]string{"hash": hash, "rid": rid}
-	resp, err := c.get(apiBase+"sync/torrentPeers", params)
+	resp, err := c.get(ctx, apiBase+"sync/torrentPeers", params)
 	if err != nil {
-		return torrentPeers, err
+		return torrentPeers, describe(err, statusMessages{
+			http.StatusNotFound: "torrent hash not found",
+		})
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&torrentPeers); err != nil {
 		return torrentPeers, err
-	} else if resp != nil && (*resp).StatusCode == http.StatusNotFound {
-		return torrentPeers, fmt.Errorf("torrent hash not found")
 	}
 	return torrentPeers, err
 }
 
+// RawTorrentPeers returns the sync/torrentPeers response as qbittorrent sent it, so
+// partial updates can be told apart from fields at their zero value
+func (c *Client) RawTorrentPeers(hash string, rid string) (data json.RawMessage, err error) {
+	return c.RawTorrentPeersCtx(context.Background(), hash, rid)
+}
+
+// RawTorrentPeersCtx is RawTorrentPeers with a context that controls the lifetime of the request
+func (c *Client) RawTorrentPeersCtx(ctx context.Context, hash string, rid string) (data json.RawMessage, err error) {
+	params := map[string]string{"hash": hash, "rid": rid}
+	resp, err := c.get(ctx, apiBase+"sync/torrentPeers", params)
+	if err != nil {
+		return data, describe(err, statusMessages{
+			http.StatusNotFound: "torrent hash not found",
+		})
+	}
+	defer resp.Body.Close()
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return data, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return data, nil
+}
+
 // Transfer Endpoints
 
 // Info returns info you usually see in qBt status bar.
 func (c *Client) Info() (info Info, err error) {
-	resp, err := c.get(apiBase+"transfer/info", nil)
+	return c.InfoCtx(context.Background())
+}
+
+// InfoCtx is Info with a context that controls the lifetime of the request
+func (c *Client) InfoCtx(ctx context.Context) (info Info, err error) {
+	resp, err := c.get(ctx, apiBase+"transfer/info", nil)
 	if err != nil {
 		return info, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
 		return info, err
 	}
@@ -407,10 +648,17 @@ func (c *Client) Info() (info Info, err error) {
 
 // AltSpeedLimitsEnabled returns the alternative speed limits state
 func (c *Client) AltSpeedLimitsEnabled() (mode bool, err error) {
-	resp, err := c.get(apiBase+"transfer/speedLimitsMode", nil)
+	return c.AltSpeedLimitsEnabledCtx(context.Background())
+}
+
+// AltSpeedLimitsEnabledCtx is AltSpeedLimitsEnabled with a context that controls the lifetime of the request
+func (c *Client) AltSpeedLimitsEnabledCtx(ctx context.Context) (mode bool, err error) {
+	resp, err := c.get(ctx, apiBase+"transfer/speedLimitsMode", nil)
 	if err != nil {
 		return mode, err
 	}
+	defer resp.Body.Close()
+
 	var decoded int
 	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
 		return mode, err
@@ -421,16 +669,31 @@ func (c *Client) AltSpeedLimitsEnabled() (mode bool, err error) {
 
 // ToggleAltSpeedLimits toggles the alternative speed limits
 func (c *Client) ToggleAltSpeedLimits() error {
-	_, err := c.post(apiBase+"transfer/toggleSpeedLimitsMode", nil)
-	return err
+	return c.ToggleAltSpeedLimitsCtx(context.Background())
+}
+
+// ToggleAltSpeedLimitsCtx is ToggleAltSpeedLimits with a context that controls the lifetime of the request
+func (c *Client) ToggleAltSpeedLimitsCtx(ctx context.Context) error {
+	resp, err := c.post(ctx, apiBase+"transfer/toggleSpeedLimitsMode", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // DlLimit returns the global download limit
 func (c *Client) DlLimit() (dlLimit int, err error) {
-	resp, err := c.get(apiBase+"transfer/downloadLimit", nil)
+	return c.DlLimitCtx(context.Background())
+}
+
+// DlLimitCtx is DlLimit with a context that controls the lifetime of the request
+func (c *Client) DlLimitCtx(ctx context.Context) (dlLimit int, err error) {
+	resp, err := c.get(ctx, apiBase+"transfer/downloadLimit", nil)
 	if err != nil {
 		return dlLimit, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&dlLimit); err != nil {
 		return dlLimit, err
 	}
@@ -439,42 +702,75 @@ func (c *Client) DlLimit() (dlLimit int, err error) {
 
 // SetDlLimit sets the global download limit
 func (c *Client) SetDlLimit(limit int) error {
+	return c.SetDlLimitCtx(context.Background(), limit)
+}
+
+// SetDlLimitCtx is SetDlLimit with a context that controls the lifetime of the request
+func (c *Client) SetDlLimitCtx(ctx context.Context, limit int) error {
 	params := map[string]string{"limit": strconv.Itoa(limit)}
-	_, err := c.post(apiBase+"transfer/setDownloadLimit", params)
-	return err
+	resp, err := c.post(ctx, apiBase+"transfer/setDownloadLimit", params)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // UlLimit returns the global upload limit
 func (c *Client) UlLimit() (ulLimit int, err error) {
-	resp, err := c.get(apiBase+"transfer/uploadLimit", nil)
+	return c.UlLimitCtx(context.Background())
+}
+
+// UlLimitCtx is UlLimit with a context that controls the lifetime of the request
+func (c *Client) UlLimitCtx(ctx context.Context) (ulLimit int, err error) {
+	resp, err := c.get(ctx, apiBase+"transfer/uploadLimit", nil)
 	if err != nil {
 		return ulLimit, err
 	}
+	defer resp.Body.Close()
+
 	json.NewDecoder(resp.Body).Decode(&ulLimit)
 	return ulLimit, err
 }
 
 // SetUlLimit sets the global upload limit
 func (c *Client) SetUlLimit(limit int) error {
+	return c.SetUlLimitCtx(context.Background(), limit)
+}
+
+// SetUlLimitCtx is SetUlLimit with a context that controls the lifetime of the request
+func (c *Client) SetUlLimitCtx(ctx context.Context, limit int) error {
 	params := map[string]string{"limit": strconv.Itoa(limit)}
-	_, err := c.post(apiBase+"transfer/setUploadLimit", params)
-	return err
+	resp, err := c.post(ctx, apiBase+"transfer/setUploadLimit", params)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // BanPeers bans the given peers
 func (c *Client) BanPeers(peers []string) (set bool, err error) {
-	params := map[string]string{"peers": delimit(peers, "%7C")}
-	resp, err := c.post(apiBase+"transfer/banPeers", params)
+	return c.BanPeersCtx(context.Background(), peers)
+}
+
+// BanPeersCtx is BanPeers with a context that controls the lifetime of the request
+func (c *Client) BanPeersCtx(ctx context.Context, peers []string) (set bool, err error) {
+	params := map[string]string{"peers": delimit(peers, "|")}
+	resp, err := c.post(ctx, apiBase+"transfer/banPeers", params)
 	if err != nil {
 		return set, err
 	}
-	return (resp.StatusCode == http.StatusOK), err
+	return true, resp.Body.Close()
 }
 
 // Torrents Endpoints
 
 // Torrents returns a list of all torrents in qbittorrent matching your filter
 func (c *Client) Torrents(opts TorrentsOptions) (torrentList []TorrentInfo, err error) {
+	return c.TorrentsCtx(context.Background(), opts)
+}
+
+// TorrentsCtx is Torrents with a context that controls the lifetime of the request
+func (c *Client) TorrentsCtx(ctx context.Context, opts TorrentsOptions) (torrentList []TorrentInfo, err error) {
 	params := map[string]string{}
 	if opts.Filter != nil {
 		params["filter"] = *opts.Filter
@@ -495,12 +791,14 @@ func (c *Client) Torrents(opts TorrentsOptions) (torrentList []TorrentInfo, err
 		params["limit"] = strconv.Itoa(*opts.Limit)
 	}
 	if opts.Hashes != nil {
-		params["hashes"] = delimit(opts.Hashes, "%7C")
+		params["hashes"] = delimit(opts.Hashes, "|")
 	}
-	resp, err := c.get(apiBase+"torrents/info", params)
+	resp, err := c.get(ctx, apiBase+"torrents/info", params)
 	if err != nil {
 		return torrentList, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&torrentList); err != nil {
 		return torrentList, err
 	}
@@ -509,11 +807,18 @@ func (c *Client) Torrents(opts TorrentsOptions) (torrentList []TorrentInfo, err
 
 // Torrent returns a specific torrent matching the hash
 func (c *Client) Torrent(hash string) (torrent Torrent, err error) {
+	return c.TorrentCtx(context.Background(), hash)
+}
+
+// TorrentCtx is Torrent with a context that controls the lifetime of the request
+func (c *Client) TorrentCtx(ctx context.Context, hash string) (torrent Torrent, err error) {
 	var opts = map[string]string{"hash": strings.ToLower(hash)}
-	resp, err := c.get(apiBase+"torrents/properties", opts)
+	resp, err := c.get(ctx, apiBase+"torrents/properties", opts)
 	if err != nil {
 		return torrent, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&torrent); err != nil {
 		return torrent, err
 	}
@@ -522,11 +827,18 @@ func (c *Client) Torrent(hash string) (torrent Torrent, err error) {
 
 // TorrentTrackers returns all trackers for a specific torrent matching the hash
 func (c *Client) TorrentTrackers(hash string) (trackers []Tracker, err error) {
+	return c.TorrentTrackersCtx(context.Background(), hash)
+}
+
+// TorrentTrackersCtx is TorrentTrackers with a context that controls the lifetime of the request
+func (c *Client) TorrentTrackersCtx(ctx context.Context, hash string) (trackers []Tracker, err error) {
 	var opts = map[string]string{"hash": strings.ToLower(hash)}
-	resp, err := c.get(apiBase+"torrents/trackers", opts)
+	resp, err := c.get(ctx, apiBase+"torrents/trackers", opts)
 	if err != nil {
 		return trackers, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&trackers); err != nil {
 		return trackers, err
 	}
@@ -535,11 +847,18 @@ func (c *Client) TorrentTrackers(hash string) (trackers []Tracker, err error) {
 
 // TorrentWebSeeds returns seeders for a specific torrent matching the hash
 func (c *Client) TorrentWebSeeds(hash string) (webSeeds []WebSeed, err error) {
+	return c.TorrentWebSeedsCtx(context.Background(), hash)
+}
+
+// TorrentWebSeedsCtx is TorrentWebSeeds with a context that controls the lifetime of the request
+func (c *Client) TorrentWebSeedsCtx(ctx context.Context, hash string) (webSeeds []WebSeed, err error) {
 	var opts = map[string]string{"hash": strings.ToLower(hash)}
-	resp, err := c.get(apiBase+"torrents/webseeds", opts)
+	resp, err := c.get(ctx, apiBase+"torrents/webseeds", opts)
 	if err != nil {
 		return webSeeds, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&webSeeds); err != nil {
 		return webSeeds, err
 	}
@@ -548,11 +867,18 @@ func (c *Client) TorrentWebSeeds(hash string) (webSeeds []WebSeed, err error) {
 
 // TorrentFiles from given hash
 func (c *Client) TorrentFiles(hash string) (files []TorrentFile, err error) {
+	return c.TorrentFilesCtx(context.Background(), hash)
+}
+
+// TorrentFilesCtx is TorrentFiles with a context that controls the lifetime of the request
+func (c *Client) TorrentFilesCtx(ctx context.Context, hash string) (files []TorrentFile, err error) {
 	var opts = map[string]string{"hash": strings.ToLower(hash)}
-	resp, err := c.get(apiBase+"torrents/files", opts)
+	resp, err := c.get(ctx, apiBase+"torrents/files", opts)
 	if err != nil {
 		return files, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
 		return files, err
 	}
@@ -561,11 +887,18 @@ func (c *Client) TorrentFiles(hash string) (files []TorrentFile, err error) {
 
 // TorrentPieceStates for all pieces of torrent
 func (c *Client) TorrentPieceStates(hash string) (states []int, err error) {
+	return c.TorrentPieceStatesCtx(context.Background(), hash)
+}
+
+// TorrentPieceStatesCtx is TorrentPieceStates with a context that controls the lifetime of the request
+func (c *Client) TorrentPieceStatesCtx(ctx context.Context, hash string) (states []int, err error) {
 	var opts = map[string]string{"hash": strings.ToLower(hash)}
-	resp, err := c.get(apiBase+"torrents/pieceStates", opts)
+	resp, err := c.get(ctx, apiBase+"torrents/pieceStates", opts)
 	if err != nil {
 		return states, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
 		return states, err
 	}
@@ -574,11 +907,18 @@ func (c *Client) TorrentPieceStates(hash string) (states []int, err error) {
 
 // TorrentPieceHashes for all pieces of torrent
 func (c *Client) TorrentPieceHashes(hash string) (hashes []string, err error) {
+	return c.TorrentPieceHashesCtx(context.Background(), hash)
+}
+
+// TorrentPieceHashesCtx is TorrentPieceHashes with a context that controls the lifetime of the request
+func (c *Client) TorrentPieceHashesCtx(ctx context.Context, hash string) (hashes []string, err error) {
 	var opts = map[string]string{"hash": strings.ToLower(hash)}
-	resp, err := c.get(apiBase+"torrents/pieceHashes", opts)
+	resp, err := c.get(ctx, apiBase+"torrents/pieceHashes", opts)
 	if err != nil {
 		return hashes, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&hashes); err != nil {
 		return hashes, err
 	}
@@ -587,52 +927,105 @@ func (c *Client) TorrentPieceHashes(hash string) (hashes []string, err error) {
 
 // Pause torrents
 func (c *Client) Pause(hashes []string) error {
+	return c.PauseCtx(context.Background(), hashes)
+}
+
+// PauseCtx is Pause with a context that controls the lifetime of the request
+func (c *Client) PauseCtx(ctx context.Context, hashes []string) error {
+	// qbittorrent 5 renamed pause to stop
+	endpoint, err := c.versionedEndpoint(ctx, "torrents/pause", "torrents/stop", apiV2_11_0)
+	if err != nil {
+		return err
+	}
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	_, err := c.post(apiBase+"torrents/stop", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+endpoint, opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // Resume torrents
 func (c *Client) Resume(hashes []string) error {
+	return c.ResumeCtx(context.Background(), hashes)
+}
+
+// ResumeCtx is Resume with a context that controls the lifetime of the request
+func (c *Client) ResumeCtx(ctx context.Context, hashes []string) error {
+	// qbittorrent 5 renamed resume to start
+	endpoint, err := c.versionedEndpoint(ctx, "torrents/resume", "torrents/start", apiV2_11_0)
+	if err != nil {
+		return err
+	}
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	_, err := c.post(apiBase+"torrents/start", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+endpoint, opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // Delete torrents and optionally delete their files
 func (c *Client) Delete(hashes []string, deleteFiles bool) error {
+	return c.DeleteCtx(context.Background(), hashes, deleteFiles)
+}
+
+// DeleteCtx is Delete with a context that controls the lifetime of the request
+func (c *Client) DeleteCtx(ctx context.Context, hashes []string, deleteFiles bool) error {
 	opts := map[string]string{
 		"hashes":      delimit(hashes, "|"),
 		"deleteFiles": strconv.FormatBool(deleteFiles),
 	}
-	_, err := c.post(apiBase+"torrents/delete", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+"torrents/delete", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // Recheck torrents
 func (c *Client) Recheck(hashes []string) error {
+	return c.RecheckCtx(context.Background(), hashes)
+}
+
+// RecheckCtx is Recheck with a context that controls the lifetime of the request
+func (c *Client) RecheckCtx(ctx context.Context, hashes []string) error {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	_, err := c.post(apiBase+"torrents/recheck", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+"torrents/recheck", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // Reannounce torrents
 func (c *Client) Reannounce(hashes []string) error {
+	return c.ReannounceCtx(context.Background(), hashes)
+}
+
+// ReannounceCtx is Reannounce with a context that controls the lifetime of the request
+func (c *Client) ReannounceCtx(ctx context.Context, hashes []string) error {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	_, err := c.post(apiBase+"torrents/reannounce", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+"torrents/reannounce", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // DownloadFromLink starts downloading a torrent from a link
 func (c *Client) DownloadLinks(links []string, opts DownloadOptions) error {
+	return c.DownloadLinksCtx(context.Background(), links, opts)
+}
+
+// DownloadLinksCtx is DownloadLinks with a context that controls the lifetime of the request
+func (c *Client) DownloadLinksCtx(ctx context.Context, links []string, opts DownloadOptions) error {
 	params := map[string]string{}
 	if len(links) == 0 {
 		return fmt.Errorf("at least one url must be present")
 	} else {
-		delimitedURLs := delimit(links, "%0A")
-		// TODO: Why is encoding causing problems now?
-		// encodedURLS := url.QueryEscape(delimitedURLs)
-		params["urls"] = delimitedURLs
+		// the request encodes the parameters itself, so the delimiter must not be escaped
+		params["urls"] = delimit(links, "\n")
 	}
 	if opts.Savepath != nil {
 		params["savepath"] = *opts.Savepath
@@ -668,18 +1061,22 @@ func (c *Client) DownloadLinks(links []string, opts DownloadOptions) error {
 		params["firstLastPiecePrio"] = strconv.FormatBool(*opts.FirstLastPiecePriority)
 	}
 
-	resp, err := c.postMultipartData(apiBase+"torrents/add", params)
+	resp, err := c.postMultipartData(ctx, apiBase+"torrents/add", params)
 	if err != nil {
-		return err
-	} else if resp.StatusCode == 415 {
-		return fmt.Errorf("torrent file is not valid")
+		return describe(err, statusMessages{
+			http.StatusUnsupportedMediaType: "torrent file is not valid",
+		})
 	}
-
-	return nil
+	return resp.Body.Close()
 }
 
 // DownloadFromFile starts downloading a torrent from a file
 func (c *Client) DownloadFiles(torrents string, opts DownloadOptions) error {
+	return c.DownloadFilesCtx(context.Background(), torrents, opts)
+}
+
+// DownloadFilesCtx is DownloadFiles with a context that controls the lifetime of the request
+func (c *Client) DownloadFilesCtx(ctx context.Context, torrents string, opts DownloadOptions) error {
 	params := map[string]string{}
 	if torrents == "" {
 		return fmt.Errorf("at least one file must be present")
@@ -720,187 +1117,188 @@ func (c *Client) DownloadFiles(torrents string, opts DownloadOptions) error {
 	if opts.FirstLastPiecePriority != nil {
 		params["firstLastPiecePrio"] = strconv.FormatBool(*opts.FirstLastPiecePriority)
 	}
-	resp, err := c.postMultipartFile(apiBase+"torrents/add", torrents, params)
+	resp, err := c.postMultipartFile(ctx, apiBase+"torrents/add", torrents, params)
 	if err != nil {
-		return err
-	} else if resp.StatusCode == 415 {
-		return fmt.Errorf("torrent file is not valid")
+		return describe(err, statusMessages{
+			http.StatusUnsupportedMediaType: "torrent file is not valid",
+		})
 	}
-
-	return nil
+	return resp.Body.Close()
 }
 
 // AddTrackers to a torrent
 func (c *Client) AddTrackers(hash string, trackers []string) error {
+	return c.AddTrackersCtx(context.Background(), hash, trackers)
+}
+
+// AddTrackersCtx is AddTrackers with a context that controls the lifetime of the request
+func (c *Client) AddTrackersCtx(ctx context.Context, hash string, trackers []string) error {
 	params := make(map[string]string)
 	params["hash"] = strings.ToLower(hash)
-	delimitedTrackers := delimit(trackers, "%0A")
-	encodedTrackers := url.QueryEscape(delimitedTrackers)
-	params["urls"] = encodedTrackers
+	params["urls"] = delimit(trackers, "\n")
 
-	resp, err := c.post(apiBase+"torrents/addTrackers", params)
+	resp, err := c.post(ctx, apiBase+"torrents/addTrackers", params)
 	if err != nil {
-		return err
-	} else if resp != nil && (*resp).StatusCode == http.StatusNotFound {
-		return fmt.Errorf("torrent hash not found")
+		return describe(err, statusMessages{
+			http.StatusNotFound: "torrent hash not found",
+		})
 	}
-	return nil
+	return resp.Body.Close()
 }
 
 // EditTracker on a torrent
 func (c *Client) EditTracker(hash string, origURL string, newURL string) error {
+	return c.EditTrackerCtx(context.Background(), hash, origURL, newURL)
+}
+
+// EditTrackerCtx is EditTracker with a context that controls the lifetime of the request
+func (c *Client) EditTrackerCtx(ctx context.Context, hash string, origURL string, newURL string) error {
 	params := map[string]string{
 		"hash":    hash,
 		"origUrl": origURL,
 		"newUrl":  newURL,
 	}
-	resp, err := c.post(apiBase+"torrents/editTracker", params)
+	resp, err := c.post(ctx, apiBase+"torrents/editTracker", params)
 	if err != nil {
-		return err
-	}
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusBadRequest:
-		return fmt.Errorf("newUrl is not a valid url")
-	case http.StatusNotFound:
-		return fmt.Errorf("torrent hash was not found")
-	case http.StatusConflict:
-		return fmt.Errorf("newUrl already exists for this torrent or origUrl was not found")
-	default:
-		return nil
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "newUrl is not a valid url",
+			http.StatusNotFound:   "torrent hash was not found",
+			http.StatusConflict:   "newUrl already exists for this torrent or origUrl was not found",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // RemoveTrackers from a torrent
 func (c *Client) RemoveTrackers(hash string, trackers []string) error {
+	return c.RemoveTrackersCtx(context.Background(), hash, trackers)
+}
+
+// RemoveTrackersCtx is RemoveTrackers with a context that controls the lifetime of the request
+func (c *Client) RemoveTrackersCtx(ctx context.Context, hash string, trackers []string) error {
 	params := map[string]string{
 		"hash": hash,
 		"urls": delimit(trackers, "|"),
 	}
-	resp, err := c.post(apiBase+"torrents/removeTrackers", params)
+	resp, err := c.post(ctx, apiBase+"torrents/removeTrackers", params)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusNotFound:
-		return fmt.Errorf("torrent hash was not found")
-	case http.StatusConflict:
-		return fmt.Errorf("all URLs were not found")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusNotFound: "torrent hash was not found",
+			http.StatusConflict: "all URLs were not found",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // IncreasePriority of torrents
 func (c *Client) IncreasePriority(hashes []string) error {
+	return c.IncreasePriorityCtx(context.Background(), hashes)
+}
+
+// IncreasePriorityCtx is IncreasePriority with a context that controls the lifetime of the request
+func (c *Client) IncreasePriorityCtx(ctx context.Context, hashes []string) error {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.post(apiBase+"torrents/increasePrio", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/increasePrio", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusConflict:
-		return fmt.Errorf("torrent queueing is not enabled")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusConflict: "torrent queueing is not enabled",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // DecreasePriority of torrents
 func (c *Client) DecreasePriority(hashes []string) error {
+	return c.DecreasePriorityCtx(context.Background(), hashes)
+}
+
+// DecreasePriorityCtx is DecreasePriority with a context that controls the lifetime of the request
+func (c *Client) DecreasePriorityCtx(ctx context.Context, hashes []string) error {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.post(apiBase+"torrents/decreasePrio", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/decreasePrio", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusConflict:
-		return fmt.Errorf("Torrent queueing is not enabled")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusConflict: "torrent queueing is not enabled",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // MaxPriority maximizes the priority of torrents
 func (c *Client) MaxPriority(hashes []string) error {
+	return c.MaxPriorityCtx(context.Background(), hashes)
+}
+
+// MaxPriorityCtx is MaxPriority with a context that controls the lifetime of the request
+func (c *Client) MaxPriorityCtx(ctx context.Context, hashes []string) error {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.post(apiBase+"torrents/topPrio", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/topPrio", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusConflict:
-		return fmt.Errorf("torrent queueing is not enabled")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusConflict: "torrent queueing is not enabled",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // MinPriority maximizes the priority of torrents
 func (c *Client) MinPriority(hashes []string) error {
+	return c.MinPriorityCtx(context.Background(), hashes)
+}
+
+// MinPriorityCtx is MinPriority with a context that controls the lifetime of the request
+func (c *Client) MinPriorityCtx(ctx context.Context, hashes []string) error {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.post(apiBase+"torrents/bottomPrio", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/bottomPrio", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusConflict:
-		return fmt.Errorf("torrent queueing is not enabled")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusConflict: "torrent queueing is not enabled",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // FilePriority for a torrent
 func (c *Client) FilePriority(hash string, ids []int, priority int) error {
+	return c.FilePriorityCtx(context.Background(), hash, ids, priority)
+}
+
+// FilePriorityCtx is FilePriority with a context that controls the lifetime of the request
+func (c *Client) FilePriorityCtx(ctx context.Context, hash string, ids []int, priority int) error {
 	formattedIds := []string{}
 	for _, id := range ids {
 		formattedIds = append(formattedIds, strconv.Itoa(id))
 	}
 
 	opts := map[string]string{
-		"hashes":   hash,
+		"hash":     hash,
 		"id":       delimit(formattedIds, "|"),
 		"priority": strconv.Itoa(priority),
 	}
-	resp, err := c.get(apiBase+"torrents/filePrio", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/filePrio", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("priority is invalid or at least one id is not an integer")
-	case http.StatusConflict:
-		return fmt.Errorf("Torrent metadata hasn't downloaded yet or at least one file id was not found")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "priority is invalid or at least one id is not an integer",
+			http.StatusConflict:   "torrent metadata hasn't downloaded yet or at least one file id was not found",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // GetTorrentDownloadLimit for a list of torrents
 func (c *Client) GetTorrentDownloadLimit(hashes []string) (limits map[string]int, err error) {
+	return c.GetTorrentDownloadLimitCtx(context.Background(), hashes)
+}
+
+// GetTorrentDownloadLimitCtx is GetTorrentDownloadLimit with a context that controls the lifetime of the request
+func (c *Client) GetTorrentDownloadLimitCtx(ctx context.Context, hashes []string) (limits map[string]int, err error) {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.post(apiBase+"torrents/downloadLimit", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/downloadLimit", opts)
 	if err != nil {
 		return limits, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&limits); err != nil {
 		return limits, err
 	}
@@ -909,44 +1307,65 @@ func (c *Client) GetTorrentDownloadLimit(hashes []string) (limits map[string]int
 
 // SetTorrentDownloadLimit for a list of torrents
 func (c *Client) SetTorrentDownloadLimit(hashes []string, limit int) error {
+	return c.SetTorrentDownloadLimitCtx(context.Background(), hashes, limit)
+}
+
+// SetTorrentDownloadLimitCtx is SetTorrentDownloadLimit with a context that controls the lifetime of the request
+func (c *Client) SetTorrentDownloadLimitCtx(ctx context.Context, hashes []string, limit int) error {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"limit":  strconv.Itoa(limit),
 	}
-	_, err := c.post(apiBase+"torrents/setDownloadLimit", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+"torrents/setDownloadLimit", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // SetTorrentShareLimit for a list of torrents
 func (c *Client) SetTorrentShareLimit(hashes []string, ratioLimit int, seedingTimeLimit int, inactiveSeedTimeLimit int) error {
+	return c.SetTorrentShareLimitCtx(context.Background(), hashes, ratioLimit, seedingTimeLimit, inactiveSeedTimeLimit)
+}
+
+// SetTorrentShareLimitCtx is SetTorrentShareLimit with a context that controls the lifetime of the request
+func (c *Client) SetTorrentShareLimitCtx(ctx context.Context, hashes []string, ratioLimit int, seedingTimeLimit int, inactiveSeedTimeLimit int) error {
 	opts := map[string]string{
-		"hashes":                   delimit(hashes, "|"),
-		"ratioLimit":               strconv.Itoa(ratioLimit),
-		"seedingTimeLimit":         strconv.Itoa(seedingTimeLimit),
-		"inactiveSeedingTimeLimit": strconv.Itoa(seedingTimeLimit),
+		"hashes":           delimit(hashes, "|"),
+		"ratioLimit":       strconv.Itoa(ratioLimit),
+		"seedingTimeLimit": strconv.Itoa(seedingTimeLimit),
 	}
-	resp, err := c.post(apiBase+"torrents/setShareLimits", opts)
+	// older servers reject the request if they don't know a parameter
+	supported, err := c.SupportsEndpoint(ctx, "torrents/setShareLimits#inactiveSeedingTimeLimit")
 	if err != nil {
 		return err
 	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("a share limit or at least one id is invalid")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+	if supported {
+		opts["inactiveSeedingTimeLimit"] = strconv.Itoa(inactiveSeedTimeLimit)
+	}
+	resp, err := c.post(ctx, apiBase+"torrents/setShareLimits", opts)
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "a share limit or at least one id is invalid",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // GetTorrentUploadLimit for a list of torrents
 func (c *Client) GetTorrentUploadLimit(hashes []string) (limits map[string]int, err error) {
+	return c.GetTorrentUploadLimitCtx(context.Background(), hashes)
+}
+
+// GetTorrentUploadLimitCtx is GetTorrentUploadLimit with a context that controls the lifetime of the request
+func (c *Client) GetTorrentUploadLimitCtx(ctx context.Context, hashes []string) (limits map[string]int, err error) {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.post(apiBase+"torrents/uploadLimit", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/uploadLimit", opts)
 	if err != nil {
 		return limits, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&limits); err != nil {
 		return limits, err
 	}
@@ -955,90 +1374,100 @@ func (c *Client) GetTorrentUploadLimit(hashes []string) (limits map[string]int,
 
 // SetTorrentUploadLimit for a list of torrents
 func (c *Client) SetTorrentUploadLimit(hashes []string, limit int) error {
+	return c.SetTorrentUploadLimitCtx(context.Background(), hashes, limit)
+}
+
+// SetTorrentUploadLimitCtx is SetTorrentUploadLimit with a context that controls the lifetime of the request
+func (c *Client) SetTorrentUploadLimitCtx(ctx context.Context, hashes []string, limit int) error {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"limit":  strconv.Itoa(limit),
 	}
-	_, err := c.post(apiBase+"torrents/setUploadLimit", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+"torrents/setUploadLimit", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // SetTorrentLocation for a list of torrents
 func (c *Client) SetTorrentLocation(hashes []string, location string) error {
+	return c.SetTorrentLocationCtx(context.Background(), hashes, location)
+}
+
+// SetTorrentLocationCtx is SetTorrentLocation with a context that controls the lifetime of the request
+func (c *Client) SetTorrentLocationCtx(ctx context.Context, hashes []string, location string) error {
 	opts := map[string]string{
 		"hashes":   delimit(hashes, "|"),
 		"location": location,
 	}
-	resp, err := c.post(apiBase+"torrents/setLocation", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/setLocation", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("save path is empty")
-	case http.StatusForbidden:
-		return fmt.Errorf("user does not have write access to directory")
-	case http.StatusConflict:
-		return fmt.Errorf("unable to create save path directory")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "save path is empty",
+			http.StatusForbidden:  "user does not have write access to directory",
+			http.StatusConflict:   "unable to create save path directory",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // SetTorrentName for a torrent
 func (c *Client) SetTorrentName(hash string, name string) error {
+	return c.SetTorrentNameCtx(context.Background(), hash, name)
+}
+
+// SetTorrentNameCtx is SetTorrentName with a context that controls the lifetime of the request
+func (c *Client) SetTorrentNameCtx(ctx context.Context, hash string, name string) error {
 	opts := map[string]string{
 		"hash": hash,
 		"name": name,
 	}
-	resp, err := c.post(apiBase+"torrents/rename", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/rename", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusNotFound:
-		return fmt.Errorf("torrent hash is invalid")
-	case http.StatusConflict:
-		return fmt.Errorf("torrent name is empty")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusNotFound: "torrent hash is invalid",
+			http.StatusConflict: "torrent name is empty",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // SetTorrentCategory for a list of torrents
 func (c *Client) SetTorrentCategory(hashes []string, category string) error {
+	return c.SetTorrentCategoryCtx(context.Background(), hashes, category)
+}
+
+// SetTorrentCategoryCtx is SetTorrentCategory with a context that controls the lifetime of the request
+func (c *Client) SetTorrentCategoryCtx(ctx context.Context, hashes []string, category string) error {
 	opts := map[string]string{
 		"hashes":   delimit(hashes, "|"),
 		"category": category,
 	}
-	resp, err := c.post(apiBase+"torrents/setCategory", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/setCategory", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusConflict:
-		return fmt.Errorf("category name does not exist")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusConflict: "category name does not exist",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // GetCategories used by client
 func (c *Client) GetCategories() (categories Categories, err error) {
-	resp, err := c.get(apiBase+"torrents/categories", nil)
+	return c.GetCategoriesCtx(context.Background())
+}
+
+// GetCategoriesCtx is GetCategories with a context that controls the lifetime of the request
+func (c *Client) GetCategoriesCtx(ctx context.Context) (categories Categories, err error) {
+	resp, err := c.get(ctx, apiBase+"torrents/categories", nil)
 	if err != nil {
 		return categories, err
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&categories); err != nil {
+	defer resp.Body.Close()
+
+	// the response is a plain object of category names to categories
+	if err := json.NewDecoder(resp.Body).Decode(&categories.Category); err != nil {
 		return categories, err
 	}
 	return categories, nil
@@ -1046,91 +1475,112 @@ func (c *Client) GetCategories() (categories Categories, err error) {
 
 // CreateCategory for use by client
 func (c *Client) CreateCategory(category string, savePath string) error {
+	return c.CreateCategoryCtx(context.Background(), category, savePath)
+}
+
+// CreateCategoryCtx is CreateCategory with a context that controls the lifetime of the request
+func (c *Client) CreateCategoryCtx(ctx context.Context, category string, savePath string) error {
 	opts := map[string]string{
 		"category": category,
 		"savePath": savePath,
 	}
-	resp, err := c.post(apiBase+"torrents/createCategory", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/createCategory", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("category name is empty")
-	case http.StatusConflict:
-		return fmt.Errorf("category name is invalid")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "category name is empty",
+			http.StatusConflict:   "category name is invalid",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // UpdateCategory used by client
 func (c *Client) UpdateCategory(category string, savePath string) error {
+	return c.UpdateCategoryCtx(context.Background(), category, savePath)
+}
+
+// UpdateCategoryCtx is UpdateCategory with a context that controls the lifetime of the request
+func (c *Client) UpdateCategoryCtx(ctx context.Context, category string, savePath string) error {
 	opts := map[string]string{
 		"category": category,
 		"savePath": savePath,
 	}
-	resp, err := c.post(apiBase+"torrents/editCategory", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/editCategory", opts)
 	if err != nil {
-		return err
-	}
-
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("category name is empty")
-	case http.StatusConflict:
-		return fmt.Errorf("category editing failed")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "category name is empty",
+			http.StatusConflict:   "category editing failed",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // DeleteCategories used by client
 func (c *Client) DeleteCategories(categories []string) error {
+	return c.DeleteCategoriesCtx(context.Background(), categories)
+}
+
+// DeleteCategoriesCtx is DeleteCategories with a context that controls the lifetime of the request
+func (c *Client) DeleteCategoriesCtx(ctx context.Context, categories []string) error {
 	opts := map[string]string{"categories": delimit(categories, "\n")}
-	_, err := c.post(apiBase+"torrents/removeCategories", opts)
-	return err
+	resp, err := c.post(ctx, apiBase+"torrents/removeCategories", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 // AddTorrentTags to a list of torrents
 func (c *Client) AddTorrentTags(hashes []string, tags []string) (bool, error) {
+	return c.AddTorrentTagsCtx(context.Background(), hashes, tags)
+}
+
+// AddTorrentTagsCtx is AddTorrentTags with a context that controls the lifetime of the request
+func (c *Client) AddTorrentTagsCtx(ctx context.Context, hashes []string, tags []string) (bool, error) {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"tags":   delimit(tags, ","),
 	}
-	resp, err := c.post(apiBase+"torrents/addTags", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/addTags", opts)
 	if err != nil {
 		return false, err
 	}
 
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // RemoveTorrentTags from a list of torrents (empty list removes all tags)
 func (c *Client) RemoveTorrentTags(hashes []string, tags []string) (bool, error) {
+	return c.RemoveTorrentTagsCtx(context.Background(), hashes, tags)
+}
+
+// RemoveTorrentTagsCtx is RemoveTorrentTags with a context that controls the lifetime of the request
+func (c *Client) RemoveTorrentTagsCtx(ctx context.Context, hashes []string, tags []string) (bool, error) {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"tags":   delimit(tags, ","),
 	}
-	resp, err := c.post(apiBase+"torrents/removeTags", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/removeTags", opts)
 	if err != nil {
 		return false, err
 	}
 
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // GetTorrentTags from a list of torrents (empty list removes all tags)
 func (c *Client) GetTorrentTags() (tags []string, err error) {
-	resp, err := c.get(apiBase+"torrents/tags", nil)
+	return c.GetTorrentTagsCtx(context.Background())
+}
+
+// GetTorrentTagsCtx is GetTorrentTags with a context that controls the lifetime of the request
+func (c *Client) GetTorrentTagsCtx(ctx context.Context) (tags []string, err error) {
+	resp, err := c.get(ctx, apiBase+"torrents/tags", nil)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
 	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
 		return tags, err
 	}
@@ -1139,127 +1589,160 @@ func (c *Client) GetTorrentTags() (tags []string, err error) {
 
 // CreateTags for use by client
 func (c *Client) CreateTags(tags []string) (bool, error) {
+	return c.CreateTagsCtx(context.Background(), tags)
+}
+
+// CreateTagsCtx is CreateTags with a context that controls the lifetime of the request
+func (c *Client) CreateTagsCtx(ctx context.Context, tags []string) (bool, error) {
 	opts := map[string]string{"tags": delimit(tags, ",")}
-	resp, err := c.post(apiBase+"torrents/createTags", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/createTags", opts)
 	if err != nil {
 		return false, err
 	}
 
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // DeleteTags used by client
 func (c *Client) DeleteTags(tags []string) (bool, error) {
+	return c.DeleteTagsCtx(context.Background(), tags)
+}
+
+// DeleteTagsCtx is DeleteTags with a context that controls the lifetime of the request
+func (c *Client) DeleteTagsCtx(ctx context.Context, tags []string) (bool, error) {
 	opts := map[string]string{"tags": delimit(tags, ",")}
-	resp, err := c.post(apiBase+"torrents/deleteTags", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/deleteTags", opts)
 	if err != nil {
 		return false, err
 	}
 
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // SetAutoManagement for a list of torrents
 func (c *Client) SetAutoManagement(hashes []string, enable bool) (bool, error) {
+	return c.SetAutoManagementCtx(context.Background(), hashes, enable)
+}
+
+// SetAutoManagementCtx is SetAutoManagement with a context that controls the lifetime of the request
+func (c *Client) SetAutoManagementCtx(ctx context.Context, hashes []string, enable bool) (bool, error) {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"enable": strconv.FormatBool(enable),
 	}
-	resp, err := c.post(apiBase+"torrents/setAutoManagement", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/setAutoManagement", opts)
 	if err != nil {
 		return false, err
 	}
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // ToggleSequentialDownload for a list of torrents
 func (c *Client) ToggleSequentialDownload(hashes []string) (bool, error) {
+	return c.ToggleSequentialDownloadCtx(context.Background(), hashes)
+}
+
+// ToggleSequentialDownloadCtx is ToggleSequentialDownload with a context that controls the lifetime of the request
+func (c *Client) ToggleSequentialDownloadCtx(ctx context.Context, hashes []string) (bool, error) {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.get(apiBase+"torrents/toggleSequentialDownload", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/toggleSequentialDownload", opts)
 	if err != nil {
 		return false, err
 	}
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // ToggleFirstLastPiecePriority for a list of torrents
 func (c *Client) ToggleFirstLastPiecePriority(hashes []string) (bool, error) {
+	return c.ToggleFirstLastPiecePriorityCtx(context.Background(), hashes)
+}
+
+// ToggleFirstLastPiecePriorityCtx is ToggleFirstLastPiecePriority with a context that controls the lifetime of the request
+func (c *Client) ToggleFirstLastPiecePriorityCtx(ctx context.Context, hashes []string) (bool, error) {
 	opts := map[string]string{"hashes": delimit(hashes, "|")}
-	resp, err := c.get(apiBase+"torrents/toggleFirstLastPiecePrio", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/toggleFirstLastPiecePrio", opts)
 	if err != nil {
 		return false, err
 	}
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // SetForceStart for a list of torrents
 func (c *Client) SetForceStart(hashes []string, value bool) (bool, error) {
+	return c.SetForceStartCtx(context.Background(), hashes, value)
+}
+
+// SetForceStartCtx is SetForceStart with a context that controls the lifetime of the request
+func (c *Client) SetForceStartCtx(ctx context.Context, hashes []string, value bool) (bool, error) {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"value":  strconv.FormatBool(value),
 	}
-	resp, err := c.post(apiBase+"torrents/setForceStart", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/setForceStart", opts)
 	if err != nil {
 		return false, err
 	}
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // SetSuperSeeding for a list of torrents
 func (c *Client) SetSuperSeeding(hashes []string, value bool) (bool, error) {
+	return c.SetSuperSeedingCtx(context.Background(), hashes, value)
+}
+
+// SetSuperSeedingCtx is SetSuperSeeding with a context that controls the lifetime of the request
+func (c *Client) SetSuperSeedingCtx(ctx context.Context, hashes []string, value bool) (bool, error) {
 	opts := map[string]string{
 		"hashes": delimit(hashes, "|"),
 		"value":  strconv.FormatBool(value),
 	}
-	resp, err := c.post(apiBase+"torrents/setSuperSeeding", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/setSuperSeeding", opts)
 	if err != nil {
 		return false, err
 	}
-	return resp.StatusCode == http.StatusOK, nil //TODO: look into other statuses
+	return true, resp.Body.Close()
 }
 
 // RenameFile for a single torrent
 func (c *Client) RenameFile(hash string, oldPath string, newPath string) (err error) {
+	return c.RenameFileCtx(context.Background(), hash, oldPath, newPath)
+}
+
+// RenameFileCtx is RenameFile with a context that controls the lifetime of the request
+func (c *Client) RenameFileCtx(ctx context.Context, hash string, oldPath string, newPath string) (err error) {
 	opts := map[string]string{
 		"hash":    hash,
 		"oldPath": oldPath,
 		"newPath": newPath,
 	}
-	resp, err := c.post(apiBase+"torrents/renameFile", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/renameFile", opts)
 	if err != nil {
-		return err
-	}
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("missing newPath parameter")
-	case http.StatusConflict:
-		return fmt.Errorf("invalid newPath or oldPath, or newPath already in use")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "missing newPath parameter",
+			http.StatusConflict:   "invalid newPath or oldPath, or newPath already in use",
+		})
 	}
+	return resp.Body.Close()
 }
 
 // RenameFolder for a single torrent
 func (c *Client) RenameFolder(hash string, oldPath string, newPath string) (err error) {
+	return c.RenameFolderCtx(context.Background(), hash, oldPath, newPath)
+}
+
+// RenameFolderCtx is RenameFolder with a context that controls the lifetime of the request
+func (c *Client) RenameFolderCtx(ctx context.Context, hash string, oldPath string, newPath string) (err error) {
 	opts := map[string]string{
 		"hash":    hash,
 		"oldPath": oldPath,
 		"newPath": newPath,
 	}
-	resp, err := c.post(apiBase+"torrents/renameFile", opts)
+	resp, err := c.post(ctx, apiBase+"torrents/renameFolder", opts)
 	if err != nil {
-		return err
-	}
-	switch sc := (*resp).StatusCode; sc {
-	case http.StatusOK:
-		return nil
-	case http.StatusBadRequest:
-		return fmt.Errorf("missing newPath parameter")
-	case http.StatusConflict:
-		return fmt.Errorf("invalid newPath or oldPath, or newPath already in use")
-	default:
-		return fmt.Errorf("an unknown error occurred causing a status code of: %d", sc)
+		return describe(err, statusMessages{
+			http.StatusBadRequest: "missing newPath parameter",
+			http.StatusConflict:   "invalid newPath or oldPath, or newPath already in use",
+		})
 	}
+	return resp.Body.Close()
 }
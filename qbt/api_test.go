@@ -0,0 +1,68 @@
+package qbt_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+func TestDownloadLinks(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	links := []string{
+		"magnet:?xt=urn:btih:1111111111111111111111111111111111111111&dn=one",
+		"magnet:?xt=urn:btih:2222222222222222222222222222222222222222&dn=two",
+	}
+	if err := client.DownloadLinks(links, qbt.DownloadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// both links arrive as separate torrents and can be listed by hash
+	torrents, err := client.Torrents(qbt.TorrentsOptions{Hashes: []string{
+		"1111111111111111111111111111111111111111",
+		"2222222222222222222222222222222222222222",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, torrent := range torrents {
+		names = append(names, torrent.Name)
+	}
+	sort.Strings(names)
+	if want := []string{"one", "two"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("added %q, want %q", names, want)
+	}
+}
+
+func TestGetCategories(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if err := client.CreateCategory("movies", "/data/movies"); err != nil {
+		t.Fatal(err)
+	}
+	categories, err := client.GetCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]qbt.Category{"movies": {Name: "movies", SavePath: "/data/movies"}}
+	if !reflect.DeepEqual(categories.Category, want) {
+		t.Errorf("categories are %v, want %v", categories.Category, want)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	if err := srv.Client().Logout(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,148 @@
+package qbt
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Severity ranks how risky a finding of AuditPreferences is
+type Severity int
+
+// Severities of findings, from least to most risky
+const (
+	SeverityLow Severity = iota + 1
+	SeverityMedium
+	SeverityHigh
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the severity by name, so findings read well as JSON
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is a risky setting reported by AuditPreferences
+type Finding struct {
+	ID         string   `json:"id"` // stable identifier of the check, e.g. "csrf-protection-disabled"
+	Severity   Severity `json:"severity"`
+	Preference string   `json:"preference"` // JSON key of the preference at fault
+	Message    string   `json:"message"`
+}
+
+// String describes the finding on one line
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.Preference, f.Message)
+}
+
+// Prefix lengths below which a subnet bypassing authentication is considered too broad
+const (
+	minWhitelistPrefixV4 = 16
+	minWhitelistPrefixV6 = 48
+)
+
+// AuditPreferences reports risky WebUI configuration, most severe first
+func AuditPreferences(prefs Preferences) []Finding {
+	var findings []Finding
+	add := func(id string, severity Severity, preference string, message string) {
+		findings = append(findings, Finding{ID: id, Severity: severity, Preference: preference, Message: message})
+	}
+
+	if !prefs.WebUICSRFProtectionEnabled {
+		add("csrf-protection-disabled", SeverityHigh, "web_ui_csrf_protection_enabled",
+			"CSRF protection is disabled, other websites can send requests with the session of a logged in user")
+	}
+	if !prefs.WebUIClickjackingProtectionEnabled {
+		add("clickjacking-protection-disabled", SeverityMedium, "web_ui_clickjacking_protection_enabled",
+			"clickjacking protection is disabled, other websites can embed the WebUI in a frame")
+	}
+	if prefs.BypassLocalAuth {
+		add("local-auth-bypassed", SeverityHigh, "bypass_local_auth",
+			"clients on localhost skip authentication, including anything proxied through it")
+	}
+	if prefs.BypassAuthSubnetWhitelistEnabled {
+		for _, subnet := range broadSubnets(prefs.BypassAuthSubnetWhitelist) {
+			add("broad-auth-whitelist", SeverityHigh, "bypass_auth_subnet_whitelist",
+				fmt.Sprintf("%s skips authentication and covers too many addresses", subnet))
+		}
+	}
+	if !prefs.UseHTTPS && !isLoopbackAddress(prefs.WebUIAddress) {
+		add("https-disabled", SeverityHigh, "use_https",
+			fmt.Sprintf("the WebUI listens on %q without HTTPS, credentials are sent in clear text", prefs.WebUIAddress))
+	}
+	if strings.TrimSpace(prefs.WebUIDomainList) == "" {
+		add("empty-domain-list", SeverityMedium, "web_ui_domain_list",
+			"no server domains are set, the Host header of requests is not restricted")
+	}
+	// the WebUI only uses UPnP while it is enabled globally
+	if prefs.UPNP && prefs.WebUIUPNPEnabled {
+		add("webui-upnp", SeverityHigh, "web_ui_upnp",
+			"UPnP forwards the WebUI port on the router, exposing it to the internet")
+	}
+	if prefs.AutorunEnabled && strings.TrimSpace(prefs.AutorunProgram) != "" {
+		add("autorun-on-finished", SeverityMedium, "autorun_program",
+			fmt.Sprintf("%q runs when a torrent finishes, anyone able to change preferences can run commands", prefs.AutorunProgram))
+	}
+	if prefs.AutorunOnTorrentAddedEnabled && strings.TrimSpace(prefs.AutorunOnTorrentAddedProgram) != "" {
+		add("autorun-on-added", SeverityMedium, "autorun_on_torrent_added_program",
+			fmt.Sprintf("%q runs when a torrent is added, anyone able to change preferences can run commands", prefs.AutorunOnTorrentAddedProgram))
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+	return findings
+}
+
+// AuditInstance reads the preferences of an instance and audits them
+func AuditInstance(ctx context.Context, app AppController) ([]Finding, error) {
+	prefs, err := app.PreferencesCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	return AuditPreferences(prefs), nil
+}
+
+// broadSubnets returns the entries of a whitelist that cover too many addresses.
+// Entries are separated by commas or newlines like in the WebUI
+func broadSubnets(whitelist string) []string {
+	var broad []string
+	for _, entry := range strings.FieldsFunc(whitelist, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		_, subnet, err := net.ParseCIDR(entry)
+		if err != nil {
+			// a single address
+			continue
+		}
+		ones, bits := subnet.Mask.Size()
+		if (bits == 32 && ones < minWhitelistPrefixV4) || (bits == 128 && ones < minWhitelistPrefixV6) {
+			broad = append(broad, entry)
+		}
+	}
+	return broad
+}
+
+// isLoopbackAddress reports whether the WebUI address only accepts local connections
+func isLoopbackAddress(address string) bool {
+	address = strings.TrimSpace(address)
+	if strings.EqualFold(address, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(address, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
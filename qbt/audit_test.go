@@ -0,0 +1,38 @@
+package qbt_test
+
+import (
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// hasFinding reports whether findings include the one with the id
+func hasFinding(findings []qbt.Finding, id string) bool {
+	for _, finding := range findings {
+		if finding.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAuditWebUIUPnP(t *testing.T) {
+	tests := []struct {
+		name  string
+		upnp  bool
+		webUI bool
+		want  bool
+	}{
+		{"enabled", true, true, true},
+		{"disabled globally", false, true, false},
+		{"disabled for the WebUI", true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := qbt.AuditPreferences(qbt.Preferences{UPNP: tt.upnp, WebUIUPNPEnabled: tt.webUI})
+			if got := hasFinding(findings, "webui-upnp"); got != tt.want {
+				t.Errorf("webui-upnp reported is %v, want %v: %v", got, tt.want, findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package qbt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// CredentialProvider returns the username and password used to (re-)authenticate with qbittorrent
+type CredentialProvider func(ctx context.Context) (username string, password string, err error)
+
+// StaticCredentials returns a CredentialProvider that always returns the given username and password
+func StaticCredentials(username string, password string) CredentialProvider {
+	return func(context.Context) (string, string, error) {
+		return username, password, nil
+	}
+}
+
+// rememberCredentials keeps the credentials of a successful login so we can log in
+// again once the session expires, unless a CredentialProvider was configured
+func (c *Client) rememberCredentials(username string, password string) {
+	if c.Credentials == nil {
+		c.Credentials = StaticCredentials(username, password)
+	}
+}
+
+// do sends the request produced by build and, when qbittorrent rejects it because
+// the session has expired, logs in again and retries the request once.
+// Requests to endpoints the server is too old for fail with ErrUnsupported.
+// build is called for every attempt so that request bodies can be replayed
+func (c *Client) do(ctx context.Context, endpoint string, build func() (*http.Request, error)) (*http.Response, error) {
+	if err := c.requireEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	session := atomic.LoadUint64(&c.session)
+	resp, err := c.sendWithRetry(ctx, endpoint, build)
+	if !c.shouldReauthenticate(endpoint, err) {
+		return resp, err
+	}
+
+	renewed, loginErr := c.reauthenticate(ctx, endpoint, session)
+	if loginErr != nil {
+		return nil, fmt.Errorf("session expired and re-authentication failed: %w", loginErr)
+	}
+	if !renewed {
+		// the endpoint refused the request for another reason
+		return resp, err
+	}
+	return c.sendWithRetry(ctx, endpoint, build)
+}
+
+// send performs a single attempt of a request
+func (c *Client) send(endpoint string, build func() (*http.Request, error)) (*http.Response, error) {
+	if c.err != nil {
+		return nil, fmt.Errorf("invalid client configuration: %w", c.err)
+	}
+
+	req, err := build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	if err := checkResponse(endpoint, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// shouldReauthenticate reports whether err means the session cookie is no longer valid
+// and we have the credentials needed to get a new one
+func (c *Client) shouldReauthenticate(endpoint string, err error) bool {
+	if c.Credentials == nil || endpoint == apiBase+"auth/login" {
+		return false
+	}
+	return errors.Is(err, ErrForbidden)
+}
+
+// reauthenticate logs in again with the client's credentials after endpoint was
+// refused with 403 Forbidden, and reports whether the session was renewed.
+// It does not log in if the session was already renewed since the caller sent its
+// request as session, or if the session turns out to be valid.
+// Concurrent callers wait for a single login instead of each performing their own
+func (c *Client) reauthenticate(ctx context.Context, endpoint string, session uint64) (bool, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if atomic.LoadUint64(&c.session) != session {
+		return true, nil
+	}
+	if !c.sessionExpired(ctx, endpoint) {
+		return false, nil
+	}
+	username, password, err := c.Credentials(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get credentials: %w", err)
+	}
+	if err := c.LoginCtx(ctx, username, password); err != nil {
+		return false, err
+	}
+	atomic.AddUint64(&c.session, 1)
+	return true, nil
+}
+
+// sessionExpired reports whether qbittorrent refused a request to endpoint with
+// 403 Forbidden because the session cookie is no longer valid. Some endpoints use
+// 403 for their own errors, e.g. torrents/setLocation for a directory that is not
+// writable, so the session is checked separately. If the check fails otherwise the
+// session is assumed valid
+func (c *Client) sessionExpired(ctx context.Context, endpoint string) bool {
+	if endpoint == apiBase+"app/version" {
+		// validateSession would only repeat the request
+		return true
+	}
+	valid, err := c.validateSession(ctx)
+	return err == nil && !valid
+}
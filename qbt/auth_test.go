@@ -0,0 +1,134 @@
+package qbt_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// countingTransport counts the requests sent to each endpoint
+type countingTransport struct {
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.requests == nil {
+		t.requests = map[string]int{}
+	}
+	t.requests[strings.TrimPrefix(req.URL.Path, "/api/v2/")]++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// count returns the number of requests sent to endpoint
+func (t *countingTransport) count(endpoint string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.requests[endpoint]
+}
+
+// reset forgets the requests counted so far
+func (t *countingTransport) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = nil
+}
+
+func TestReauthenticateOnForbidden(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	transport := &countingTransport{}
+	client := srv.Client(qbt.WithTransport(transport))
+	transport.reset()
+
+	srv.ExpireSessions()
+	if _, err := client.ApplicationVersion(); err != nil {
+		t.Fatalf("request after the session expired: %v", err)
+	}
+	if n := transport.count("auth/login"); n != 1 {
+		t.Errorf("logged in %d times, want 1", n)
+	}
+}
+
+func TestReauthenticateWithoutCredentials(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := qbt.NewClient(srv.URL)
+	if _, err := client.ApplicationVersion(); !errors.Is(err, qbt.ErrForbidden) {
+		t.Fatalf("got %v, want ErrForbidden", err)
+	}
+}
+
+func TestReauthenticateConcurrently(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	transport := &countingTransport{}
+	client := srv.Client(qbt.WithTransport(transport))
+	// cache the Web API version so the requests below only race on the session
+	if _, err := client.APIVersion(); err != nil {
+		t.Fatal(err)
+	}
+	transport.reset()
+	srv.ExpireSessions()
+
+	var wg sync.WaitGroup
+	var failed int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Preferences(); err != nil {
+				t.Errorf("request after the session expired: %v", err)
+				atomic.AddInt32(&failed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed == 0 {
+		if n := transport.count("auth/login"); n != 1 {
+			t.Errorf("logged in %d times for concurrent requests, want 1", n)
+		}
+	}
+}
+
+func TestForbiddenWithValidSession(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	// qbittorrent also answers 403 when the session is fine but the user cannot write to the directory
+	fake := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/torrents/setLocation" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	})
+
+	transport := &countingTransport{}
+	client := srv.Client(qbt.WithTransport(transport))
+	transport.reset()
+
+	err := client.SetTorrentLocation([]string{"0123456789abcdef0123456789abcdef01234567"}, "/readonly")
+	if !errors.Is(err, qbt.ErrForbidden) {
+		t.Fatalf("got %v, want ErrForbidden", err)
+	}
+	if n := transport.count("auth/login"); n != 0 {
+		t.Errorf("logged in %d times with a valid session, want 0", n)
+	}
+	if n := transport.count("torrents/setLocation"); n != 1 {
+		t.Errorf("sent torrents/setLocation %d times, want 1", n)
+	}
+}
@@ -0,0 +1,93 @@
+package qbt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody caps how much of an error response is kept on an APIError
+const maxErrorBody = 4096
+
+// Sentinel errors for the HTTP statuses qbittorrent uses to report failures.
+// They can be matched against any error returned by the client with errors.Is
+var (
+	ErrBadRequest           = errors.New("bad request")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrNotFound             = errors.New("not found")
+	ErrConflict             = errors.New("conflict")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// APIError is returned when qbittorrent answers a request with a non-2xx status
+type APIError struct {
+	Endpoint   string // endpoint relative to api/v2/, e.g. "torrents/info"
+	StatusCode int
+	Body       string
+	Message    string // endpoint specific meaning of the status, if documented
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = strings.TrimSpace(e.Body)
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s (status %d)", e.Endpoint, msg, e.StatusCode)
+}
+
+// Unwrap returns the sentinel error matching the status code so that
+// errors.Is(err, ErrNotFound) and friends work on an APIError
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnsupportedMediaType:
+		return ErrUnsupportedMediaType
+	default:
+		return nil
+	}
+}
+
+// statusMessages maps status codes to the meaning an endpoint documents for them
+type statusMessages map[int]string
+
+// checkResponse turns a non-2xx response into an APIError, consuming and closing its body
+func checkResponse(endpoint string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &APIError{
+		Endpoint:   strings.TrimPrefix(endpoint, apiBase),
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+}
+
+// describe attaches the endpoint specific meaning of a status to an APIError
+func describe(err error, messages statusMessages) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if msg, ok := messages[apiErr.StatusCode]; ok {
+			apiErr.Message = msg
+		}
+	}
+	return err
+}
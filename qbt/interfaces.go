@@ -0,0 +1,257 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// The interfaces below describe the Client by role so that code depending on
+// qbittorrent can accept the narrowest one it needs and be tested with a fake.
+
+// AppController manages the qbittorrent application and the session with it
+type AppController interface {
+	Login(username string, password string) (err error)
+	LoginCtx(ctx context.Context, username string, password string) (err error)
+	Logout() (err error)
+	LogoutCtx(ctx context.Context) (err error)
+	ApplicationVersion() (version string, err error)
+	ApplicationVersionCtx(ctx context.Context) (version string, err error)
+	WebAPIVersion() (version string, err error)
+	WebAPIVersionCtx(ctx context.Context) (version string, err error)
+	APIVersion() (APIVersion, error)
+	APIVersionCtx(ctx context.Context) (APIVersion, error)
+	SupportsEndpoint(ctx context.Context, endpoint string) (bool, error)
+	BuildInfo() (buildInfo BuildInfo, err error)
+	BuildInfoCtx(ctx context.Context) (buildInfo BuildInfo, err error)
+	Preferences() (prefs Preferences, err error)
+	PreferencesCtx(ctx context.Context) (prefs Preferences, err error)
+	RawPreferences() (data json.RawMessage, err error)
+	RawPreferencesCtx(ctx context.Context) (data json.RawMessage, err error)
+	SetPreferences(patch PreferencesPatch) (prefsSet bool, err error)
+	SetPreferencesCtx(ctx context.Context, patch PreferencesPatch) (prefsSet bool, err error)
+	DefaultSavePath() (path string, err error)
+	DefaultSavePathCtx(ctx context.Context) (path string, err error)
+	Shutdown() error
+	ShutdownCtx(ctx context.Context) error
+}
+
+// LogReader reads the qbittorrent logs
+type LogReader interface {
+	Logs(filters map[string]string) (logs []Log, err error)
+	LogsCtx(ctx context.Context, filters map[string]string) (logs []Log, err error)
+	PeerLogs(filters map[string]string) (logs []PeerLog, err error)
+	PeerLogsCtx(ctx context.Context, filters map[string]string) (logs []PeerLog, err error)
+}
+
+// SyncReader reads incremental updates of the qbittorrent state
+type SyncReader interface {
+	MainData(rid string) (mainData MainData, err error)
+	MainDataCtx(ctx context.Context, rid string) (mainData MainData, err error)
+	RawMainData(rid string) (data json.RawMessage, err error)
+	RawMainDataCtx(ctx context.Context, rid string) (data json.RawMessage, err error)
+	TorrentPeers(hash string, rid string) (torrentPeers TorrentPeers, err error)
+	TorrentPeersCtx(ctx context.Context, hash string, rid string) (torrentPeers TorrentPeers, err error)
+	RawTorrentPeers(hash string, rid string) (data json.RawMessage, err error)
+	RawTorrentPeersCtx(ctx context.Context, hash string, rid string) (data json.RawMessage, err error)
+}
+
+// TransferController reads and changes the global transfer settings
+type TransferController interface {
+	Info() (info Info, err error)
+	InfoCtx(ctx context.Context) (info Info, err error)
+	AltSpeedLimitsEnabled() (mode bool, err error)
+	AltSpeedLimitsEnabledCtx(ctx context.Context) (mode bool, err error)
+	ToggleAltSpeedLimits() error
+	ToggleAltSpeedLimitsCtx(ctx context.Context) error
+	DlLimit() (dlLimit int, err error)
+	DlLimitCtx(ctx context.Context) (dlLimit int, err error)
+	SetDlLimit(limit int) error
+	SetDlLimitCtx(ctx context.Context, limit int) error
+	UlLimit() (ulLimit int, err error)
+	UlLimitCtx(ctx context.Context) (ulLimit int, err error)
+	SetUlLimit(limit int) error
+	SetUlLimitCtx(ctx context.Context, limit int) error
+	BanPeers(peers []string) (set bool, err error)
+	BanPeersCtx(ctx context.Context, peers []string) (set bool, err error)
+}
+
+// TorrentReader reads torrents and their categories and tags
+type TorrentReader interface {
+	Torrents(opts TorrentsOptions) (torrentList []TorrentInfo, err error)
+	TorrentsCtx(ctx context.Context, opts TorrentsOptions) (torrentList []TorrentInfo, err error)
+	Torrent(hash string) (torrent Torrent, err error)
+	TorrentCtx(ctx context.Context, hash string) (torrent Torrent, err error)
+	TorrentTrackers(hash string) (trackers []Tracker, err error)
+	TorrentTrackersCtx(ctx context.Context, hash string) (trackers []Tracker, err error)
+	TorrentWebSeeds(hash string) (webSeeds []WebSeed, err error)
+	TorrentWebSeedsCtx(ctx context.Context, hash string) (webSeeds []WebSeed, err error)
+	TorrentFiles(hash string) (files []TorrentFile, err error)
+	TorrentFilesCtx(ctx context.Context, hash string) (files []TorrentFile, err error)
+	TorrentPieceStates(hash string) (states []int, err error)
+	TorrentPieceStatesCtx(ctx context.Context, hash string) (states []int, err error)
+	TorrentPieceHashes(hash string) (hashes []string, err error)
+	TorrentPieceHashesCtx(ctx context.Context, hash string) (hashes []string, err error)
+	GetTorrentDownloadLimit(hashes []string) (limits map[string]int, err error)
+	GetTorrentDownloadLimitCtx(ctx context.Context, hashes []string) (limits map[string]int, err error)
+	GetTorrentUploadLimit(hashes []string) (limits map[string]int, err error)
+	GetTorrentUploadLimitCtx(ctx context.Context, hashes []string) (limits map[string]int, err error)
+	GetCategories() (categories Categories, err error)
+	GetCategoriesCtx(ctx context.Context) (categories Categories, err error)
+	GetTorrentTags() (tags []string, err error)
+	GetTorrentTagsCtx(ctx context.Context) (tags []string, err error)
+}
+
+// TorrentWriter adds, removes and changes torrents and their categories and tags
+type TorrentWriter interface {
+	Pause(hashes []string) error
+	PauseCtx(ctx context.Context, hashes []string) error
+	Resume(hashes []string) error
+	ResumeCtx(ctx context.Context, hashes []string) error
+	Delete(hashes []string, deleteFiles bool) error
+	DeleteCtx(ctx context.Context, hashes []string, deleteFiles bool) error
+	Recheck(hashes []string) error
+	RecheckCtx(ctx context.Context, hashes []string) error
+	Reannounce(hashes []string) error
+	ReannounceCtx(ctx context.Context, hashes []string) error
+	DownloadLinks(links []string, opts DownloadOptions) error
+	DownloadLinksCtx(ctx context.Context, links []string, opts DownloadOptions) error
+	DownloadFiles(torrents string, opts DownloadOptions) error
+	DownloadFilesCtx(ctx context.Context, torrents string, opts DownloadOptions) error
+	AddTrackers(hash string, trackers []string) error
+	AddTrackersCtx(ctx context.Context, hash string, trackers []string) error
+	EditTracker(hash string, origURL string, newURL string) error
+	EditTrackerCtx(ctx context.Context, hash string, origURL string, newURL string) error
+	RemoveTrackers(hash string, trackers []string) error
+	RemoveTrackersCtx(ctx context.Context, hash string, trackers []string) error
+	IncreasePriority(hashes []string) error
+	IncreasePriorityCtx(ctx context.Context, hashes []string) error
+	DecreasePriority(hashes []string) error
+	DecreasePriorityCtx(ctx context.Context, hashes []string) error
+	MaxPriority(hashes []string) error
+	MaxPriorityCtx(ctx context.Context, hashes []string) error
+	MinPriority(hashes []string) error
+	MinPriorityCtx(ctx context.Context, hashes []string) error
+	FilePriority(hash string, ids []int, priority int) error
+	FilePriorityCtx(ctx context.Context, hash string, ids []int, priority int) error
+	SetTorrentDownloadLimit(hashes []string, limit int) error
+	SetTorrentDownloadLimitCtx(ctx context.Context, hashes []string, limit int) error
+	SetTorrentShareLimit(hashes []string, ratioLimit int, seedingTimeLimit int, inactiveSeedTimeLimit int) error
+	SetTorrentShareLimitCtx(ctx context.Context, hashes []string, ratioLimit int, seedingTimeLimit int, inactiveSeedTimeLimit int) error
+	SetTorrentUploadLimit(hashes []string, limit int) error
+	SetTorrentUploadLimitCtx(ctx context.Context, hashes []string, limit int) error
+	SetTorrentLocation(hashes []string, location string) error
+	SetTorrentLocationCtx(ctx context.Context, hashes []string, location string) error
+	SetTorrentName(hash string, name string) error
+	SetTorrentNameCtx(ctx context.Context, hash string, name string) error
+	SetTorrentCategory(hashes []string, category string) error
+	SetTorrentCategoryCtx(ctx context.Context, hashes []string, category string) error
+	CreateCategory(category string, savePath string) error
+	CreateCategoryCtx(ctx context.Context, category string, savePath string) error
+	UpdateCategory(category string, savePath string) error
+	UpdateCategoryCtx(ctx context.Context, category string, savePath string) error
+	DeleteCategories(categories []string) error
+	DeleteCategoriesCtx(ctx context.Context, categories []string) error
+	AddTorrentTags(hashes []string, tags []string) (bool, error)
+	AddTorrentTagsCtx(ctx context.Context, hashes []string, tags []string) (bool, error)
+	RemoveTorrentTags(hashes []string, tags []string) (bool, error)
+	RemoveTorrentTagsCtx(ctx context.Context, hashes []string, tags []string) (bool, error)
+	CreateTags(tags []string) (bool, error)
+	CreateTagsCtx(ctx context.Context, tags []string) (bool, error)
+	DeleteTags(tags []string) (bool, error)
+	DeleteTagsCtx(ctx context.Context, tags []string) (bool, error)
+	SetAutoManagement(hashes []string, enable bool) (bool, error)
+	SetAutoManagementCtx(ctx context.Context, hashes []string, enable bool) (bool, error)
+	ToggleSequentialDownload(hashes []string) (bool, error)
+	ToggleSequentialDownloadCtx(ctx context.Context, hashes []string) (bool, error)
+	ToggleFirstLastPiecePriority(hashes []string) (bool, error)
+	ToggleFirstLastPiecePriorityCtx(ctx context.Context, hashes []string) (bool, error)
+	SetForceStart(hashes []string, value bool) (bool, error)
+	SetForceStartCtx(ctx context.Context, hashes []string, value bool) (bool, error)
+	SetSuperSeeding(hashes []string, value bool) (bool, error)
+	SetSuperSeedingCtx(ctx context.Context, hashes []string, value bool) (bool, error)
+	RenameFile(hash string, oldPath string, newPath string) (err error)
+	RenameFileCtx(ctx context.Context, hash string, oldPath string, newPath string) (err error)
+	RenameFolder(hash string, oldPath string, newPath string) (err error)
+	RenameFolderCtx(ctx context.Context, hash string, oldPath string, newPath string) (err error)
+}
+
+// RSSManager manages RSS folders, feeds and their articles
+type RSSManager interface {
+	AddRSSFolder(path string) error
+	AddRSSFolderCtx(ctx context.Context, path string) error
+	AddRSSFeed(url string, path string) error
+	AddRSSFeedCtx(ctx context.Context, url string, path string) error
+	RemoveRSSItem(path string) error
+	RemoveRSSItemCtx(ctx context.Context, path string) error
+	MoveRSSItem(itemPath string, destPath string) error
+	MoveRSSItemCtx(ctx context.Context, itemPath string, destPath string) error
+	RSSItems(withData bool) (root RSSFolder, err error)
+	RSSItemsCtx(ctx context.Context, withData bool) (root RSSFolder, err error)
+	MarkRSSAsRead(itemPath string, articleID string) error
+	MarkRSSAsReadCtx(ctx context.Context, itemPath string, articleID string) error
+	RefreshRSSItem(itemPath string) error
+	RefreshRSSItemCtx(ctx context.Context, itemPath string) error
+	SetRSSFeedURL(path string, url string) error
+	SetRSSFeedURLCtx(ctx context.Context, path string, url string) error
+}
+
+// RSSRuleManager manages the RSS auto-downloading rules
+type RSSRuleManager interface {
+	SetRSSRule(name string, rule RSSRule) error
+	SetRSSRuleCtx(ctx context.Context, name string, rule RSSRule) error
+	RenameRSSRule(name string, newName string) error
+	RenameRSSRuleCtx(ctx context.Context, name string, newName string) error
+	RemoveRSSRule(name string) error
+	RemoveRSSRuleCtx(ctx context.Context, name string) error
+	RSSRules() (rules map[string]RSSRule, err error)
+	RSSRulesCtx(ctx context.Context) (rules map[string]RSSRule, err error)
+	RSSMatchingArticles(name string) (articles map[string][]string, err error)
+	RSSMatchingArticlesCtx(ctx context.Context, name string) (articles map[string][]string, err error)
+}
+
+// SearchEngine runs searches with the search plugins of qbittorrent
+type SearchEngine interface {
+	StartSearch(pattern string, category string, plugins ...string) (id int, err error)
+	StartSearchCtx(ctx context.Context, pattern string, category string, plugins ...string) (id int, err error)
+	StopSearch(id int) error
+	StopSearchCtx(ctx context.Context, id int) error
+	SearchStatus(id int) (job SearchJob, err error)
+	SearchStatusCtx(ctx context.Context, id int) (job SearchJob, err error)
+	SearchJobs() (jobs []SearchJob, err error)
+	SearchJobsCtx(ctx context.Context) (jobs []SearchJob, err error)
+	SearchResults(id int, offset int, limit int) (results SearchResults, err error)
+	SearchResultsCtx(ctx context.Context, id int, offset int, limit int) (results SearchResults, err error)
+	DeleteSearch(id int) error
+	DeleteSearchCtx(ctx context.Context, id int) error
+}
+
+// SearchPluginManager manages the search plugins of qbittorrent
+type SearchPluginManager interface {
+	SearchPlugins() (plugins []SearchPlugin, err error)
+	SearchPluginsCtx(ctx context.Context) (plugins []SearchPlugin, err error)
+	InstallSearchPlugins(sources []string) error
+	InstallSearchPluginsCtx(ctx context.Context, sources []string) error
+	UninstallSearchPlugins(names []string) error
+	UninstallSearchPluginsCtx(ctx context.Context, names []string) error
+	EnableSearchPlugins(names []string, enable bool) error
+	EnableSearchPluginsCtx(ctx context.Context, names []string, enable bool) error
+	UpdateSearchPlugins() error
+	UpdateSearchPluginsCtx(ctx context.Context) error
+}
+
+// API is implemented by Client and covers the whole Web API the client supports
+type API interface {
+	AppController
+	LogReader
+	SyncReader
+	TransferController
+	TorrentReader
+	TorrentWriter
+	RSSManager
+	RSSRuleManager
+	SearchEngine
+	SearchPluginManager
+}
+
+// ensure Client implements API
+var _ API = (*Client)(nil)
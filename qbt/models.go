@@ -1,5 +1,7 @@
 package qbt
 
+import "encoding/json"
+
 // BasicTorrent holds a basic torrent object from qbittorrent
 type BasicTorrent struct {
 	Category               string `json:"category"`
@@ -139,28 +141,47 @@ type TorrentFile struct {
 	PieceRange   []int   `json:"piece_range"`
 }
 
-// serverState holds the server state struct
-type serverState struct {
-	ConnectionStatus  string `json:"connection_status"`
-	DhtNodes          int    `json:"dht_nodes"`
-	DlInfoData        int    `json:"dl_info_data"`
-	DlInfoSpeed       int    `json:"dl_info_speed"`
-	DlRateLimit       int    `json:"dl_rate_limit"`
-	Queueing          bool   `json:"queueing"`
-	RefreshInterval   int    `json:"refresh_interval"`
-	UpInfoData        int    `json:"up_info_data"`
-	UpInfoSpeed       int    `json:"up_info_speed"`
-	UpRateLimit       int    `json:"up_rate_limit"`
-	UseAltSpeedLimits bool   `json:"use_alt_speed_limits"`
+// ServerState holds the global transfer state reported by sync/maindata
+type ServerState struct {
+	AlltimeDl            int64  `json:"alltime_dl"`
+	AlltimeUl            int64  `json:"alltime_ul"`
+	AverageTimeQueue     int    `json:"average_time_queue"`
+	ConnectionStatus     string `json:"connection_status"`
+	DhtNodes             int    `json:"dht_nodes"`
+	DlInfoData           int    `json:"dl_info_data"`
+	DlInfoSpeed          int    `json:"dl_info_speed"`
+	DlRateLimit          int    `json:"dl_rate_limit"`
+	FreeSpaceOnDisk      int64  `json:"free_space_on_disk"`
+	GlobalRatio          string `json:"global_ratio"`
+	LastExternalAddrV4   string `json:"last_external_address_v4"`
+	LastExternalAddrV6   string `json:"last_external_address_v6"`
+	QueuedIOJobs         int    `json:"queued_io_jobs"`
+	Queueing             bool   `json:"queueing"`
+	ReadCacheHits        string `json:"read_cache_hits"`
+	ReadCacheOverload    string `json:"read_cache_overload"`
+	RefreshInterval      int    `json:"refresh_interval"`
+	TotalBuffersSize     int64  `json:"total_buffers_size"`
+	TotalPeerConnections int    `json:"total_peer_connections"`
+	TotalQueuedSize      int64  `json:"total_queued_size"`
+	TotalWastedSession   int64  `json:"total_wasted_session"`
+	UpInfoData           int    `json:"up_info_data"`
+	UpInfoSpeed          int    `json:"up_info_speed"`
+	UpRateLimit          int    `json:"up_rate_limit"`
+	UseAltSpeedLimits    bool   `json:"use_alt_speed_limits"`
+	UseSubcategories     bool   `json:"use_subcategories"`
+	WriteCacheOverload   string `json:"write_cache_overload"`
 }
 
 // Sync holds the sync response struct which contains
 // the server state and a map of infohashes to Torrents
+//
+// Deprecated: sync/maindata only sends what changed since the previous response and
+// reports categories as objects, which Sync cannot decode. Use SyncState to merge the updates
 type Sync struct {
 	Categories  []string           `json:"categories"`
 	FullUpdate  bool               `json:"full_update"`
 	Rid         int                `json:"rid"`
-	ServerState serverState        `json:"server_state"`
+	ServerState ServerState        `json:"server_state"`
 	Torrents    map[string]Torrent `json:"torrents"`
 }
 
@@ -172,108 +193,250 @@ type BuildInfo struct {
 	AppBitness        int    `json:"bitness"`
 }
 
+// Preferences of the qbittorrent client, grouped like the WebUI options dialog.
+// Keys this version of the library doesn't know are kept in Extra, so preferences
+// read from a newer qbittorrent are written back without losing anything
 type Preferences struct {
-	Locale                             string                 `json:"locale"`
-	CreateSubfolderEnabled             bool                   `json:"create_subfolder_enabled"`
-	StartPausedEnabled                 bool                   `json:"start_paused_enabled"`
-	AutoDeleteMode                     int                    `json:"auto_delete_mode"`
-	PreallocateAll                     bool                   `json:"preallocate_all"`
-	IncompleteFilesExt                 bool                   `json:"incomplete_files_ext"`
-	AutoTMMEnabled                     bool                   `json:"auto_tmm_enabled"`
-	TorrentChangedTMMEnabled           bool                   `json:"torrent_changed_tmm_enabled"`
-	SavePathChangedTMMEnabled          bool                   `json:"save_path_changed_tmm_enabled"`
-	CategoryChangedTMMEnabled          bool                   `json:"category_changed_tmm_enabled"`
-	SavePath                           string                 `json:"save_path"`
-	TempPathEnabled                    bool                   `json:"temp_path_enabled"`
-	TempPath                           string                 `json:"temp_path"`
-	ScanDirs                           map[string]interface{} `json:"scan_dirs"`
-	ExportDir                          string                 `json:"export_dir"`
-	ExportDirFin                       string                 `json:"export_dir_fin"`
-	MailNotificationEnabled            bool                   `json:"mail_notification_enabled"`
-	MailNotificationSender             string                 `json:"mail_notification_sender"`
-	MailNotificationEmail              string                 `json:"mail_notification_email"`
-	MailNotificationSMPTP              string                 `json:"mail_notification_smtp"`
-	MailNotificationSSLEnabled         bool                   `json:"mail_notification_ssl_enabled"`
-	MailNotificationAuthEnabled        bool                   `json:"mail_notification_auth_enabled"`
-	MailNotificationUsername           string                 `json:"mail_notification_username"`
-	MailNotificationPassword           string                 `json:"mail_notification_password"`
-	AutorunEnabled                     bool                   `json:"autorun_enabled"`
-	AutorunProgram                     string                 `json:"autorun_program"`
-	QueueingEnabled                    bool                   `json:"queueing_enabled"`
-	MaxActiveDls                       int                    `json:"max_active_downloads"`
-	MaxActiveTorrents                  int                    `json:"max_active_torrents"`
-	MaxActiveUls                       int                    `json:"max_active_uploads"`
-	DontCountSlowTorrents              bool                   `json:"dont_count_slow_torrents"`
-	SlowTorrentDlRateThreshold         int                    `json:"slow_torrent_dl_rate_threshold"`
-	SlowTorrentUlRateThreshold         int                    `json:"slow_torrent_ul_rate_threshold"`
-	SlowTorrentInactiveTimer           int                    `json:"slow_torrent_inactive_timer"`
-	MaxRatioEnabled                    bool                   `json:"max_ratio_enabled"`
-	MaxRatio                           float64                `json:"max_ratio"`
-	MaxRatioAct                        float64                `json:"max_ratio_act"`
-	ListenPort                         int                    `json:"listen_port"`
-	UPNP                               bool                   `json:"upnp"`
-	RandomPort                         bool                   `json:"random_port"`
-	DlLimit                            int                    `json:"dl_limit"`
-	UlLimit                            int                    `json:"up_limit"`
-	MaxConnections                     int                    `json:"max_connec"`
-	MaxConnectionsPerTorrent           int                    `json:"max_connec_per_torrent"`
-	MaxUls                             int                    `json:"max_uploads"`
-	MaxUlsPerTorrent                   int                    `json:"max_uploads_per_torrent"`
-	UTPEnabled                         bool                   `json:"enable_utp"`
-	LimitUTPRate                       bool                   `json:"limit_utp_rate"`
-	LimitTCPOverhead                   bool                   `json:"limit_tcp_overhead"`
-	LimitLANPeers                      bool                   `json:"limit_lan_peers"`
-	AltDlLimit                         int                    `json:"alt_dl_limit"`
-	AltUlLimit                         int                    `json:"alt_up_limit"`
-	SchedulerEnabled                   bool                   `json:"scheduler_enabled"`
-	ScheduleFromHour                   int                    `json:"schedule_from_hour"`
-	ScheduleFromMin                    int                    `json:"schedule_from_min"`
-	ScheduleToHour                     int                    `json:"schedule_to_hour"`
-	ScheduleToMin                      int                    `json:"schedule_to_min"`
-	SchedulerDays                      int                    `json:"scheduler_days"`
-	DHTEnabled                         bool                   `json:"dht"`
-	DHTSameAsBT                        bool                   `json:"dhtSameAsBT"`
-	DHTPort                            int                    `json:"dht_port"`
-	PexEnabled                         bool                   `json:"pex"`
-	LSDEnabled                         bool                   `json:"lsd"`
-	Encryption                         int                    `json:"encryption"`
-	AnonymousMode                      bool                   `json:"anonymous_mode"`
-	ProxyType                          string                 `json:"proxy_type"`
-	ProxyIP                            string                 `json:"proxy_ip"`
-	ProxyPort                          int                    `json:"proxy_port"`
-	ProxyPeerConnections               bool                   `json:"proxy_peer_connections"`
-	ForceProxy                         bool                   `json:"force_proxy"`
-	ProxyAuthEnabled                   bool                   `json:"proxy_auth_enabled"`
-	ProxyUsername                      string                 `json:"proxy_username"`
-	ProxyPassword                      string                 `json:"proxy_password"`
-	IPFilterEnabled                    bool                   `json:"ip_filter_enabled"`
-	IPFilterPath                       string                 `json:"ip_filter_path"`
-	IPFilterTrackers                   bool                   `json:"ip_filter_trackers"`
-	WebUIDomainList                    string                 `json:"web_ui_domain_list"`
-	WebUIAddress                       string                 `json:"web_ui_address"`
-	WebUIPort                          int                    `json:"web_ui_port"`
-	WebUIUPNPEnabled                   bool                   `json:"web_ui_upnp"`
-	WebUIUsername                      string                 `json:"web_ui_username"`
-	WebUIPassword                      string                 `json:"web_ui_password"`
-	WebUICSRFProtectionEnabled         bool                   `json:"web_ui_csrf_protection_enabled"`
-	WebUIClickjackingProtectionEnabled bool                   `json:"web_ui_clickjacking_protection_enabled"`
-	BypassLocalAuth                    bool                   `json:"bypass_local_auth"`
-	BypassAuthSubnetWhitelistEnabled   bool                   `json:"bypass_auth_subnet_whitelist_enabled"`
-	BypassAuthSubnetWhitelist          string                 `json:"bypass_auth_subnet_whitelist"`
-	AltWebUIEnabled                    bool                   `json:"alternative_webui_enabled"`
-	AltWebUIPath                       string                 `json:"alternative_webui_path"`
-	UseHTTPS                           bool                   `json:"use_https"`
-	SSLKey                             string                 `json:"ssl_key"`
-	SSLCert                            string                 `json:"ssl_cert"`
-	DynDNSEnabled                      bool                   `json:"dyndns_enabled"`
-	DynDNSService                      int                    `json:"dyndns_service"`
-	DynDNSUsername                     string                 `json:"dyndns_username"`
-	DynDNSPassword                     string                 `json:"dyndns_password"`
-	DynDNSDomain                       string                 `json:"dyndns_domain"`
-	RSSRefreshInterval                 int                    `json:"rss_refresh_interval"`
-	RSSMaxArtPerFeed                   int                    `json:"rss_max_articles_per_feed"`
-	RSSProcessingEnabled               bool                   `json:"rss_processing_enabled"`
-	RSSAutoDlEnabled                   bool                   `json:"rss_auto_downloading_enabled"`
+	// Behavior
+	Locale               string `json:"locale"`
+	PerformanceWarning   bool   `json:"performance_warning"`
+	FileLogEnabled       bool   `json:"file_log_enabled"`
+	FileLogPath          string `json:"file_log_path"`
+	FileLogBackupEnabled bool   `json:"file_log_backup_enabled"`
+	FileLogMaxSize       int    `json:"file_log_max_size"`
+	FileLogDeleteOld     bool   `json:"file_log_delete_old"`
+	FileLogAge           int    `json:"file_log_age"`
+	FileLogAgeType       int    `json:"file_log_age_type"`
+
+	// Downloads
+	TorrentContentLayout         string                 `json:"torrent_content_layout"`
+	CreateSubfolderEnabled       bool                   `json:"create_subfolder_enabled"`
+	AddToTopOfQueue              bool                   `json:"add_to_top_of_queue"`
+	StartPausedEnabled           bool                   `json:"start_paused_enabled"`
+	AddStoppedEnabled            bool                   `json:"add_stopped_enabled"`
+	TorrentStopCondition         string                 `json:"torrent_stop_condition"`
+	MergeTrackers                bool                   `json:"merge_trackers"`
+	AutoDeleteMode               int                    `json:"auto_delete_mode"`
+	PreallocateAll               bool                   `json:"preallocate_all"`
+	IncompleteFilesExt           bool                   `json:"incomplete_files_ext"`
+	UseUnwantedFolder            bool                   `json:"use_unwanted_folder"`
+	AutoTMMEnabled               bool                   `json:"auto_tmm_enabled"`
+	TorrentChangedTMMEnabled     bool                   `json:"torrent_changed_tmm_enabled"`
+	SavePathChangedTMMEnabled    bool                   `json:"save_path_changed_tmm_enabled"`
+	CategoryChangedTMMEnabled    bool                   `json:"category_changed_tmm_enabled"`
+	UseSubcategories             bool                   `json:"use_subcategories"`
+	UseCategoryPathsInManualMode bool                   `json:"use_category_paths_in_manual_mode"`
+	SavePath                     string                 `json:"save_path"`
+	TempPathEnabled              bool                   `json:"temp_path_enabled"`
+	TempPath                     string                 `json:"temp_path"`
+	ScanDirs                     map[string]interface{} `json:"scan_dirs"`
+	ExportDir                    string                 `json:"export_dir"`
+	ExportDirFin                 string                 `json:"export_dir_fin"`
+	ExcludedFileNamesEnabled     bool                   `json:"excluded_file_names_enabled"`
+	ExcludedFileNames            string                 `json:"excluded_file_names"`
+	MailNotificationEnabled      bool                   `json:"mail_notification_enabled"`
+	MailNotificationSender       string                 `json:"mail_notification_sender"`
+	MailNotificationEmail        string                 `json:"mail_notification_email"`
+	MailNotificationSMPTP        string                 `json:"mail_notification_smtp"`
+	MailNotificationSSLEnabled   bool                   `json:"mail_notification_ssl_enabled"`
+	MailNotificationAuthEnabled  bool                   `json:"mail_notification_auth_enabled"`
+	MailNotificationUsername     string                 `json:"mail_notification_username"`
+	MailNotificationPassword     string                 `json:"mail_notification_password"`
+	AutorunOnTorrentAddedEnabled bool                   `json:"autorun_on_torrent_added_enabled"`
+	AutorunOnTorrentAddedProgram string                 `json:"autorun_on_torrent_added_program"`
+	AutorunEnabled               bool                   `json:"autorun_enabled"`
+	AutorunProgram               string                 `json:"autorun_program"`
+
+	// Connection
+	BittorrentProtocol       int    `json:"bittorrent_protocol"`
+	ListenPort               int    `json:"listen_port"`
+	UPNP                     bool   `json:"upnp"`
+	RandomPort               bool   `json:"random_port"`
+	MaxConnections           int    `json:"max_connec"`
+	MaxConnectionsPerTorrent int    `json:"max_connec_per_torrent"`
+	MaxUls                   int    `json:"max_uploads"`
+	MaxUlsPerTorrent         int    `json:"max_uploads_per_torrent"`
+	I2PEnabled               bool   `json:"i2p_enabled"`
+	I2PAddress               string `json:"i2p_address"`
+	I2PPort                  int    `json:"i2p_port"`
+	I2PMixedMode             bool   `json:"i2p_mixed_mode"`
+	I2PInboundQuantity       int    `json:"i2p_inbound_quantity"`
+	I2POutboundQuantity      int    `json:"i2p_outbound_quantity"`
+	I2PInboundLength         int    `json:"i2p_inbound_length"`
+	I2POutboundLength        int    `json:"i2p_outbound_length"`
+	ProxyType                string `json:"proxy_type"`
+	ProxyIP                  string `json:"proxy_ip"`
+	ProxyPort                int    `json:"proxy_port"`
+	ProxyAuthEnabled         bool   `json:"proxy_auth_enabled"`
+	ProxyUsername            string `json:"proxy_username"`
+	ProxyPassword            string `json:"proxy_password"`
+	ProxyHostnameLookup      bool   `json:"proxy_hostname_lookup"`
+	ProxyBittorrent          bool   `json:"proxy_bittorrent"`
+	ProxyPeerConnections     bool   `json:"proxy_peer_connections"`
+	ProxyRSS                 bool   `json:"proxy_rss"`
+	ProxyMisc                bool   `json:"proxy_misc"`
+	ForceProxy               bool   `json:"force_proxy"`
+	IPFilterEnabled          bool   `json:"ip_filter_enabled"`
+	IPFilterPath             string `json:"ip_filter_path"`
+	IPFilterTrackers         bool   `json:"ip_filter_trackers"`
+	BannedIPs                string `json:"banned_IPs"` // one per line
+
+	// Speed
+	DlLimit          int  `json:"dl_limit"`
+	UlLimit          int  `json:"up_limit"`
+	AltDlLimit       int  `json:"alt_dl_limit"`
+	AltUlLimit       int  `json:"alt_up_limit"`
+	UTPEnabled       bool `json:"enable_utp"`
+	LimitUTPRate     bool `json:"limit_utp_rate"`
+	LimitTCPOverhead bool `json:"limit_tcp_overhead"`
+	LimitLANPeers    bool `json:"limit_lan_peers"`
+	SchedulerEnabled bool `json:"scheduler_enabled"`
+	ScheduleFromHour int  `json:"schedule_from_hour"`
+	ScheduleFromMin  int  `json:"schedule_from_min"`
+	ScheduleToHour   int  `json:"schedule_to_hour"`
+	ScheduleToMin    int  `json:"schedule_to_min"`
+	SchedulerDays    int  `json:"scheduler_days"`
+
+	// BitTorrent
+	DHTEnabled                    bool    `json:"dht"`
+	DHTSameAsBT                   bool    `json:"dhtSameAsBT"`
+	DHTPort                       int     `json:"dht_port"`
+	PexEnabled                    bool    `json:"pex"`
+	LSDEnabled                    bool    `json:"lsd"`
+	Encryption                    int     `json:"encryption"`
+	AnonymousMode                 bool    `json:"anonymous_mode"`
+	MaxActiveCheckingTorrents     int     `json:"max_active_checking_torrents"`
+	QueueingEnabled               bool    `json:"queueing_enabled"`
+	MaxActiveDls                  int     `json:"max_active_downloads"`
+	MaxActiveTorrents             int     `json:"max_active_torrents"`
+	MaxActiveUls                  int     `json:"max_active_uploads"`
+	DontCountSlowTorrents         bool    `json:"dont_count_slow_torrents"`
+	SlowTorrentDlRateThreshold    int     `json:"slow_torrent_dl_rate_threshold"`
+	SlowTorrentUlRateThreshold    int     `json:"slow_torrent_ul_rate_threshold"`
+	SlowTorrentInactiveTimer      int     `json:"slow_torrent_inactive_timer"`
+	MaxRatioEnabled               bool    `json:"max_ratio_enabled"`
+	MaxRatio                      float64 `json:"max_ratio"`
+	MaxSeedingTimeEnabled         bool    `json:"max_seeding_time_enabled"`
+	MaxSeedingTime                int     `json:"max_seeding_time"`
+	MaxInactiveSeedingTimeEnabled bool    `json:"max_inactive_seeding_time_enabled"`
+	MaxInactiveSeedingTime        int     `json:"max_inactive_seeding_time"`
+	MaxRatioAct                   int     `json:"max_ratio_act"`
+	AddTrackersEnabled            bool    `json:"add_trackers_enabled"`
+	AddTrackers                   string  `json:"add_trackers"` // one per line
+
+	// RSS
+	RSSRefreshInterval              int    `json:"rss_refresh_interval"`
+	RSSFetchDelay                   int    `json:"rss_fetch_delay"`
+	RSSMaxArtPerFeed                int    `json:"rss_max_articles_per_feed"`
+	RSSProcessingEnabled            bool   `json:"rss_processing_enabled"`
+	RSSAutoDlEnabled                bool   `json:"rss_auto_downloading_enabled"`
+	RSSDownloadRepackProperEpisodes bool   `json:"rss_download_repack_proper_episodes"`
+	RSSSmartEpisodeFilters          string `json:"rss_smart_episode_filters"`
+
+	// WebUI
+	WebUIDomainList                    string `json:"web_ui_domain_list"`
+	WebUIAddress                       string `json:"web_ui_address"`
+	WebUIPort                          int    `json:"web_ui_port"`
+	WebUIUPNPEnabled                   bool   `json:"web_ui_upnp"`
+	WebUIUsername                      string `json:"web_ui_username"`
+	WebUIPassword                      string `json:"web_ui_password"`
+	WebUICSRFProtectionEnabled         bool   `json:"web_ui_csrf_protection_enabled"`
+	WebUIClickjackingProtectionEnabled bool   `json:"web_ui_clickjacking_protection_enabled"`
+	WebUISecureCookieEnabled           bool   `json:"web_ui_secure_cookie_enabled"`
+	WebUIMaxAuthFailCount              int    `json:"web_ui_max_auth_fail_count"`
+	WebUIBanDuration                   int    `json:"web_ui_ban_duration"`
+	WebUISessionTimeout                int    `json:"web_ui_session_timeout"`
+	WebUIHostHeaderValidationEnabled   bool   `json:"web_ui_host_header_validation_enabled"`
+	WebUIUseCustomHTTPHeadersEnabled   bool   `json:"web_ui_use_custom_http_headers_enabled"`
+	WebUICustomHTTPHeaders             string `json:"web_ui_custom_http_headers"`
+	WebUIReverseProxyEnabled           bool   `json:"web_ui_reverse_proxy_enabled"`
+	WebUIReverseProxiesList            string `json:"web_ui_reverse_proxies_list"`
+	BypassLocalAuth                    bool   `json:"bypass_local_auth"`
+	BypassAuthSubnetWhitelistEnabled   bool   `json:"bypass_auth_subnet_whitelist_enabled"`
+	BypassAuthSubnetWhitelist          string `json:"bypass_auth_subnet_whitelist"`
+	AltWebUIEnabled                    bool   `json:"alternative_webui_enabled"`
+	AltWebUIPath                       string `json:"alternative_webui_path"`
+	UseHTTPS                           bool   `json:"use_https"`
+	SSLKey                             string `json:"ssl_key"`
+	SSLCert                            string `json:"ssl_cert"`
+	WebUIHTTPSKeyPath                  string `json:"web_ui_https_key_path"`
+	WebUIHTTPSCertPath                 string `json:"web_ui_https_cert_path"`
+	DynDNSEnabled                      bool   `json:"dyndns_enabled"`
+	DynDNSService                      int    `json:"dyndns_service"`
+	DynDNSUsername                     string `json:"dyndns_username"`
+	DynDNSPassword                     string `json:"dyndns_password"`
+	DynDNSDomain                       string `json:"dyndns_domain"`
+
+	// Advanced
+	ResumeDataStorageType        string `json:"resume_data_storage_type"`
+	TorrentContentRemoveOption   string `json:"torrent_content_remove_option"`
+	MemoryWorkingSetLimit        int    `json:"memory_working_set_limit"`
+	CurrentNetworkInterface      string `json:"current_network_interface"`
+	CurrentInterfaceName         string `json:"current_interface_name"`
+	CurrentInterfaceAddress      string `json:"current_interface_address"`
+	SaveResumeDataInterval       int    `json:"save_resume_data_interval"`
+	TorrentFileSizeLimit         int64  `json:"torrent_file_size_limit"`
+	RecheckCompletedTorrents     bool   `json:"recheck_completed_torrents"`
+	AppInstanceName              string `json:"app_instance_name"`
+	RefreshInterval              int    `json:"refresh_interval"`
+	ResolvePeerCountries         bool   `json:"resolve_peer_countries"`
+	ReannounceWhenAddressChanged bool   `json:"reannounce_when_address_changed"`
+	MarkOfTheWeb                 bool   `json:"mark_of_the_web"`
+	PythonExecutablePath         string `json:"python_executable_path"`
+	StatusBarExternalIP          bool   `json:"status_bar_external_ip"`
+
+	// Advanced: libtorrent
+	BdecodeDepthLimit                int    `json:"bdecode_depth_limit"`
+	BdecodeTokenLimit                int    `json:"bdecode_token_limit"`
+	AsyncIOThreads                   int    `json:"async_io_threads"`
+	HashingThreads                   int    `json:"hashing_threads"`
+	FilePoolSize                     int    `json:"file_pool_size"`
+	CheckingMemoryUse                int    `json:"checking_memory_use"`
+	DiskCache                        int    `json:"disk_cache"`
+	DiskCacheTTL                     int    `json:"disk_cache_ttl"`
+	DiskQueueSize                    int    `json:"disk_queue_size"`
+	DiskIOType                       int    `json:"disk_io_type"`
+	DiskIOReadMode                   int    `json:"disk_io_read_mode"`
+	DiskIOWriteMode                  int    `json:"disk_io_write_mode"`
+	EnableOSCache                    bool   `json:"enable_os_cache"`
+	EnableCoalesceReadWrite          bool   `json:"enable_coalesce_read_write"`
+	EnablePieceExtentAffinity        bool   `json:"enable_piece_extent_affinity"`
+	EnableUploadSuggestions          bool   `json:"enable_upload_suggestions"`
+	SendBufferWatermark              int    `json:"send_buffer_watermark"`
+	SendBufferLowWatermark           int    `json:"send_buffer_low_watermark"`
+	SendBufferWatermarkFactor        int    `json:"send_buffer_watermark_factor"`
+	ConnectionSpeed                  int    `json:"connection_speed"`
+	SocketSendBufferSize             int    `json:"socket_send_buffer_size"`
+	SocketReceiveBufferSize          int    `json:"socket_receive_buffer_size"`
+	SocketBacklogSize                int    `json:"socket_backlog_size"`
+	OutgoingPortsMin                 int    `json:"outgoing_ports_min"`
+	OutgoingPortsMax                 int    `json:"outgoing_ports_max"`
+	UPNPLeaseDuration                int    `json:"upnp_lease_duration"`
+	PeerTOS                          int    `json:"peer_tos"`
+	UTPTCPMixedMode                  int    `json:"utp_tcp_mixed_mode"`
+	IDNSupportEnabled                bool   `json:"idn_support_enabled"`
+	EnableMultiConnectionsFromSameIP bool   `json:"enable_multi_connections_from_same_ip"`
+	ValidateHTTPSTrackerCertificate  bool   `json:"validate_https_tracker_certificate"`
+	SSRFMitigation                   bool   `json:"ssrf_mitigation"`
+	BlockPeersOnPrivilegedPorts      bool   `json:"block_peers_on_privileged_ports"`
+	EnableEmbeddedTracker            bool   `json:"enable_embedded_tracker"`
+	EmbeddedTrackerPort              int    `json:"embedded_tracker_port"`
+	EmbeddedTrackerPortForwarding    bool   `json:"embedded_tracker_port_forwarding"`
+	UploadSlotsBehavior              int    `json:"upload_slots_behavior"`
+	UploadChokingAlgorithm           int    `json:"upload_choking_algorithm"`
+	AnnounceToAllTrackers            bool   `json:"announce_to_all_trackers"`
+	AnnounceToAllTiers               bool   `json:"announce_to_all_tiers"`
+	AnnounceIP                       string `json:"announce_ip"`
+	MaxConcurrentHTTPAnnounces       int    `json:"max_concurrent_http_announces"`
+	StopTrackerTimeout               int    `json:"stop_tracker_timeout"`
+	PeerTurnover                     int    `json:"peer_turnover"`
+	PeerTurnoverCutoff               int    `json:"peer_turnover_cutoff"`
+	PeerTurnoverInterval             int    `json:"peer_turnover_interval"`
+	RequestQueueSize                 int    `json:"request_queue_size"`
+	DHTBootstrapNodes                string `json:"dht_bootstrap_nodes"`
+
+	// Extra holds the preferences this version of the library doesn't know by their JSON key
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Log
@@ -293,17 +456,19 @@ type PeerLog struct {
 	Reason    string `json:"reason"`
 }
 
-// MainData
+// MainData is a single sync/maindata response. Unless FullUpdate is set it only holds
+// what changed since the previous rid, and fields missing from the response are left
+// at their zero value; use SyncState to keep a complete copy of the state
 type MainData struct {
-	Rid               int                `json:"rid"`
-	FullUpdate        bool               `json:"full_update"`
-	Torrents          map[string]Torrent `json:"torrents"`
-	TorrentsRemoved   []string           `json:"torrents_removed"`
-	Categories        []string           `json:"categories"`
-	CategoriesRemoved []string           `json:"categories_removed"`
-	Tags              []string           `json:"tags"`
-	TagsRemoved       []string           `json:"tags_removed"`
-	ServerState       serverState        `json:"server_state"`
+	Rid               int                    `json:"rid"`
+	FullUpdate        bool                   `json:"full_update"`
+	Torrents          map[string]TorrentInfo `json:"torrents"`
+	TorrentsRemoved   []string               `json:"torrents_removed"`
+	Categories        map[string]Category    `json:"categories"`
+	CategoriesRemoved []string               `json:"categories_removed"`
+	Tags              []string               `json:"tags"`
+	TagsRemoved       []string               `json:"tags_removed"`
+	ServerState       ServerState            `json:"server_state"`
 }
 
 // Main Data Options
@@ -319,30 +484,32 @@ type TorrentPeersOptions struct {
 
 // Torrent Peer
 type TorrentPeer struct {
-	Client       string `json:"client"`
-	Connection   string `json:"connection"`
-	Country      string `json:"country"`
-	CountryCode  string `json:"country_code"`
-	DlSpeed      int    `json:"dl_speed"`
-	Downloaded   int    `json:"downloaded"`
-	Files        string `json:"files"`
-	Flags        string `json:"flags"`
-	FlagsDesc    string `json:"flags_desc"`
-	IP           string `json:"ip"`
-	PeerIDClient string `json:"peer_id_client"`
-	Port         int    `json:"port"`
-	Progress     int    `json:"progress"`
-	Relevance    int    `json:"relevance"`
-	UpSpeed      int    `json:"up_speed"`
-	Uploaded     int    `json:"uploaded"`
-}
-
-// Torrent Peers
+	Client       string  `json:"client"`
+	Connection   string  `json:"connection"`
+	Country      string  `json:"country"`
+	CountryCode  string  `json:"country_code"`
+	DlSpeed      int     `json:"dl_speed"`
+	Downloaded   int64   `json:"downloaded"`
+	Files        string  `json:"files"`
+	Flags        string  `json:"flags"`
+	FlagsDesc    string  `json:"flags_desc"`
+	IP           string  `json:"ip"`
+	PeerIDClient string  `json:"peer_id_client"`
+	Port         int     `json:"port"`
+	Progress     float64 `json:"progress"`
+	Relevance    float64 `json:"relevance"`
+	UpSpeed      int     `json:"up_speed"`
+	Uploaded     int64   `json:"uploaded"`
+}
+
+// Torrent Peers is a single sync/torrentPeers response. Unless FullUpdate is set it only
+// holds what changed since the previous rid; use PeerWatcher to keep the complete peer set
 type TorrentPeers struct {
-	FullUpdate bool                   `json:"full_update"`
-	Peers      map[string]TorrentPeer `json:"peers"`
-	Rid        int                    `json:"rid"`
-	ShowFlags  bool                   `json:"show_flags"`
+	FullUpdate   bool                   `json:"full_update"`
+	Peers        map[string]TorrentPeer `json:"peers"` // by ip:port
+	PeersRemoved []string               `json:"peers_removed"`
+	Rid          int                    `json:"rid"`
+	ShowFlags    bool                   `json:"show_flags"`
 }
 
 // Info
@@ -397,3 +564,142 @@ type DownloadOptions struct {
 	AutomaticTorrentManagement *bool
 	FirstLastPiecePriority     *bool
 }
+
+// RSSArticle is an article of an RSS feed
+type RSSArticle struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Date        string `json:"date"`
+	Author      string `json:"author"`
+	Category    string `json:"category"`
+	Link        string `json:"link"`
+	TorrentURL  string `json:"torrentURL"`
+	IsRead      bool   `json:"isRead"`
+}
+
+// RSSFeed is an RSS feed. Everything but UID and URL is only set when requested with data
+type RSSFeed struct {
+	UID           string       `json:"uid"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	LastBuildDate string       `json:"lastBuildDate"`
+	IsLoading     bool         `json:"isLoading"`
+	HasError      bool         `json:"hasError"`
+	Articles      []RSSArticle `json:"articles"`
+}
+
+// RSSFolder is a folder of RSS feeds, the root folder holds all RSS items
+type RSSFolder struct {
+	Folders map[string]RSSFolder // by name
+	Feeds   map[string]RSSFeed   // by name
+}
+
+// RSSRule is an RSS auto-downloading rule in the form the WebUI exports it
+type RSSRule struct {
+	Enabled  bool `json:"enabled"`
+	Priority int  `json:"priority"` // rules are tried in ascending priority, since qBittorrent 4.6
+	// UseRegex makes MustContain and MustNotContain regular expressions instead of
+	// wildcard expressions
+	UseRegex bool `json:"useRegex"`
+	// MustContain and MustNotContain match article titles. Without UseRegex they are
+	// alternatives separated by "|", each made of words separated by spaces that must all
+	// be in the title; words may hold the wildcards "*" and "?"
+	MustContain    string `json:"mustContain"`
+	MustNotContain string `json:"mustNotContain"`
+	// EpisodeFilter selects episodes of a season, e.g. "1x2;8-15;20-;" for episode 2,
+	// episodes 8 to 15 and episodes from 20 of season 1
+	EpisodeFilter string   `json:"episodeFilter"`
+	AffectedFeeds []string `json:"affectedFeeds"` // urls of the feeds the rule applies to
+	LastMatch     string   `json:"lastMatch"`     // RFC 2822 date of the last download
+	IgnoreDays    int      `json:"ignoreDays"`    // days to ignore matches after the last one, 0 to never ignore
+	// SmartFilter downloads each episode once, recognized by the smart episode filters
+	// of the preferences, and keeps the downloaded ones in PreviouslyMatchedEpisodes
+	SmartFilter               bool             `json:"smartFilter"`
+	PreviouslyMatchedEpisodes []string         `json:"previouslyMatchedEpisodes"`
+	TorrentParams             RSSTorrentParams `json:"torrentParams"` // since qBittorrent 4.6, left out if empty
+
+	// Before qBittorrent 4.6 the torrent parameters were set by the keys below, which
+	// are still exported for compatibility
+	SavePath             string  `json:"savePath"`
+	AssignedCategory     string  `json:"assignedCategory"`
+	AddPaused            *bool   `json:"addPaused"`
+	TorrentContentLayout *string `json:"torrentContentLayout"`
+
+	// Extra holds the keys of the rule not modeled above, so rules of newer
+	// qbittorrent releases survive a round trip
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// RSSTorrentParams are the parameters of the torrents added by an RSS rule.
+// Unset fields take the defaults of qbittorrent
+type RSSTorrentParams struct {
+	Category                 string   `json:"category,omitempty"`
+	Tags                     []string `json:"tags,omitempty"`
+	SavePath                 string   `json:"save_path,omitempty"`
+	UseDownloadPath          *bool    `json:"use_download_path,omitempty"`
+	DownloadPath             string   `json:"download_path,omitempty"`
+	UseAutoTMM               *bool    `json:"use_auto_tmm,omitempty"`
+	OperatingMode            string   `json:"operating_mode,omitempty"` // "AutoManaged" or "Forced"
+	AddToQueueTop            *bool    `json:"add_to_top_of_queue,omitempty"`
+	Stopped                  *bool    `json:"stopped,omitempty"`
+	Paused                   *bool    `json:"paused,omitempty"` // qBittorrent 4.6, replaced by Stopped
+	StopCondition            string   `json:"stop_condition,omitempty"`
+	ContentLayout            string   `json:"content_layout,omitempty"`
+	SkipChecking             *bool    `json:"skip_checking,omitempty"`
+	UploadLimit              *int     `json:"upload_limit,omitempty"`
+	DownloadLimit            *int     `json:"download_limit,omitempty"`
+	SeedingTimeLimit         *int     `json:"seeding_time_limit,omitempty"`
+	InactiveSeedingTimeLimit *int     `json:"inactive_seeding_time_limit,omitempty"`
+	ShareLimitAction         string   `json:"share_limit_action,omitempty"`
+	RatioLimit               *float64 `json:"ratio_limit,omitempty"`
+}
+
+// Statuses of a search job
+const (
+	SearchRunning = "Running"
+	SearchStopped = "Stopped"
+)
+
+// SearchJob is the status of a search started with StartSearch
+type SearchJob struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"` // SearchRunning or SearchStopped
+	Total  int    `json:"total"`  // number of results found so far
+}
+
+// SearchResult is a torrent found by a search
+type SearchResult struct {
+	FileName   string `json:"fileName"`
+	FileURL    string `json:"fileUrl"` // torrent or magnet link to pass to DownloadLinks
+	FileSize   int64  `json:"fileSize"`
+	NbSeeders  int    `json:"nbSeeders"`
+	NbLeechers int    `json:"nbLeechers"`
+	DescrLink  string `json:"descrLink"` // page of the torrent on the site
+	SiteURL    string `json:"siteUrl"`
+	EngineName string `json:"engineName"` // since qBittorrent 5.0
+	PubDate    int64  `json:"pubDate"`    // unix time, since qBittorrent 5.0
+}
+
+// SearchResults is a page of the results of a search
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+	Status  string         `json:"status"` // SearchRunning or SearchStopped
+	Total   int            `json:"total"`  // number of results found so far
+}
+
+// SearchPlugin is a search plugin installed in qbittorrent
+type SearchPlugin struct {
+	Name                string           `json:"name"` // used to select and manage the plugin
+	FullName            string           `json:"fullName"`
+	Version             string           `json:"version"`
+	URL                 string           `json:"url"`
+	Enabled             bool             `json:"enabled"`
+	SupportedCategories []SearchCategory `json:"supportedCategories"`
+}
+
+// SearchCategory is a category a search plugin supports, e.g. "tv"
+type SearchCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
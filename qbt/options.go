@@ -0,0 +1,196 @@
+package qbt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultUserAgent identifies the library to qbittorrent
+	defaultUserAgent = "go-qbittorrent v0.2"
+
+	// unixScheme selects a unix domain socket, e.g. unix:///run/qbt.sock
+	unixScheme = "unix://"
+	// unixHost stands in for the host of requests sent over a unix domain socket
+	unixHost = "localhost"
+)
+
+// DialContextFunc opens the connections requests are sent over, see net.Dialer.DialContext
+type DialContextFunc func(ctx context.Context, network string, addr string) (net.Conn, error)
+
+// Option configures a Client created by NewClient
+type Option func(*options)
+
+// options collects everything set through Option before the client is built
+type options struct {
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	timeout     time.Duration
+	userAgent   string
+	headers     http.Header
+	basePath    string
+	credentials CredentialProvider
+	retryPolicy *RetryPolicy
+
+	tlsConfig   *tls.Config
+	rootCAs     *x509.CertPool
+	clientCerts []tls.Certificate
+	pins        []string
+	insecure    bool
+
+	dialContext DialContextFunc
+	sessionFile string
+}
+
+// WithHTTPClient makes the client send requests through a copy of httpClient.
+// Its cookie jar is used for the session if it has one
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the RoundTripper used to send requests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout limits the time a single request may take, including reading the response
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent replaces the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header that is sent with every request
+func WithHeader(key string, value string) Option {
+	return func(o *options) {
+		if o.headers == nil {
+			o.headers = http.Header{}
+		}
+		o.headers.Add(key, value)
+	}
+}
+
+// WithBasePath serves the API from a path prefix, for qbittorrent instances behind a reverse proxy
+func WithBasePath(basePath string) Option {
+	return func(o *options) {
+		o.basePath = basePath
+	}
+}
+
+// WithCredentials sets the username and password used to log in again when the session expires
+func WithCredentials(username string, password string) Option {
+	return WithCredentialProvider(StaticCredentials(username, password))
+}
+
+// WithCredentialProvider sets where credentials come from when the session expires
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(o *options) {
+		o.credentials = provider
+	}
+}
+
+// WithRetryPolicy retries requests that fail with transient errors
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithDialContext opens connections with dial instead of over TCP to the host in the url.
+// A unix:// url already dials the socket, so the client fails every request if both are given
+func WithDialContext(dial DialContextFunc) Option {
+	return func(o *options) {
+		o.dialContext = dial
+	}
+}
+
+// unixSocketURL turns a unix:///path/to/socket url into the http url requests are
+// built with and sets up a dialer that connects to the socket instead. It fails if
+// a dialer was set with WithDialContext, which the socket would silently replace
+func (o *options) unixSocketURL(url string) (string, error) {
+	if !strings.HasPrefix(url, unixScheme) {
+		return url, nil
+	}
+	if o.dialContext != nil {
+		return url, fmt.Errorf("a %s url cannot be combined with WithDialContext", unixScheme)
+	}
+
+	socket := strings.TrimPrefix(url, unixScheme)
+	o.dialContext = func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+	return "http://" + unixHost + "/", nil
+}
+
+// configureTransport applies the transport level options to rt, which is
+// cloned first so that a transport shared with other clients is never modified
+func (o *options) configureTransport(rt http.RoundTripper) (http.RoundTripper, error) {
+	if !o.hasTLS() && o.dialContext == nil {
+		return rt, nil
+	}
+
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("transport options require an *http.Transport, got %T", rt)
+	}
+	transport := base.Clone()
+
+	if o.dialContext != nil {
+		transport.DialContext = o.dialContext
+	}
+	if o.hasTLS() {
+		config, err := o.buildTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = config
+	}
+
+	return transport, nil
+}
+
+// joinBasePath appends a path prefix to a url, keeping the trailing "/" the client relies on
+func joinBasePath(url string, basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return url
+	}
+	return url + basePath + "/"
+}
+
+// setHeaders adds the headers sent with every request
+func (c *Client) setHeaders(req *http.Request) {
+	for key, values := range c.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// add user-agent header to allow qbittorrent to identify us
+	userAgent := c.userAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+}
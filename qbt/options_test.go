@@ -0,0 +1,59 @@
+package qbt_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// serveUnixSocket serves the fake on a unix domain socket and returns its unix:// url
+func serveUnixSocket(t *testing.T, srv *qbttest.Server) string {
+	socket := filepath.Join(t.TempDir(), "qbt.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix domain sockets are not available: %v", err)
+	}
+	server := &http.Server{Handler: srv.Config.Handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return "unix://" + socket
+}
+
+func TestUnixSocket(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := qbt.NewClient(serveUnixSocket(t, srv))
+	if err := client.Login(qbttest.DefaultUsername, qbttest.DefaultPassword); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ApplicationVersion(); err != nil {
+		t.Fatalf("request over the socket: %v", err)
+	}
+}
+
+func TestUnixSocketWithDialContext(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	dialed := false
+	dial := func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		dialed = true
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, addr)
+	}
+	client := qbt.NewClient(serveUnixSocket(t, srv), qbt.WithDialContext(dial))
+	err := client.Login(qbttest.DefaultUsername, qbttest.DefaultPassword)
+	if err == nil || !strings.Contains(err.Error(), "WithDialContext") {
+		t.Errorf("got %v, want an error about combining the socket with WithDialContext", err)
+	}
+	if dialed {
+		t.Error("dialed with the ignored dialer")
+	}
+}
@@ -0,0 +1,310 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PeerConnected is emitted when a peer connects to a watched torrent
+type PeerConnected struct {
+	Hash string
+	Addr string // ip:port, the key qbittorrent identifies the peer by
+	Peer TorrentPeer
+}
+
+// PeerDisconnected is emitted when a peer leaves a watched torrent, with its last known state
+type PeerDisconnected struct {
+	Hash string
+	Addr string
+	Peer TorrentPeer
+}
+
+func (e PeerConnected) TorrentHash() string    { return e.Hash }
+func (e PeerDisconnected) TorrentHash() string { return e.Hash }
+
+// torrentPeersUpdate is a sync/torrentPeers response in the raw form PeerWatcher merges
+type torrentPeersUpdate struct {
+	Rid          int               `json:"rid"`
+	FullUpdate   bool              `json:"full_update"`
+	Peers        map[string]fields `json:"peers"`
+	PeersRemoved []string          `json:"peers_removed"`
+}
+
+// peerState is the merged peer set of a single torrent
+type peerState struct {
+	rid    int
+	synced bool // whether the first update has been applied
+	peers  map[string]fields
+}
+
+// PeerWatcher keeps the live peer set of one or more torrents up to date by applying
+// the incremental updates of sync/torrentPeers. It is safe for concurrent use
+type PeerWatcher struct {
+	// Interval between polls. Zero polls every 1.5 seconds, like the WebUI
+	Interval time.Duration
+	// EmitExisting emits PeerConnected for the peers present on the first poll of a
+	// torrent instead of silently taking them as the starting point
+	EmitExisting bool
+
+	reader SyncReader
+
+	// updateMu serializes updates so each one applies on top of the previous rid
+	updateMu sync.Mutex
+
+	mu       sync.RWMutex
+	torrents map[string]*peerState
+	err      error
+}
+
+// NewPeerWatcher returns a PeerWatcher for the peers of the torrents with the given
+// hashes, reading from a Client or a fake of it
+func NewPeerWatcher(reader SyncReader, hashes ...string) *PeerWatcher {
+	w := &PeerWatcher{reader: reader, torrents: map[string]*peerState{}}
+	for _, hash := range hashes {
+		w.Add(hash)
+	}
+	return w
+}
+
+// Add starts watching the peers of a torrent
+func (w *PeerWatcher) Add(hash string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	hash = strings.ToLower(hash)
+	if _, ok := w.torrents[hash]; !ok {
+		w.torrents[hash] = &peerState{peers: map[string]fields{}}
+	}
+}
+
+// Remove stops watching the peers of a torrent
+func (w *PeerWatcher) Remove(hash string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.torrents, strings.ToLower(hash))
+}
+
+// Hashes returns the hashes of the watched torrents
+func (w *PeerWatcher) Hashes() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	hashes := make([]string, 0, len(w.torrents))
+	for hash := range w.torrents {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// Peers returns the peers of a watched torrent as of the last update, by ip:port
+func (w *PeerWatcher) Peers(hash string) map[string]TorrentPeer {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	state, ok := w.torrents[strings.ToLower(hash)]
+	if !ok {
+		return nil
+	}
+	peers := make(map[string]TorrentPeer, len(state.peers))
+	for addr, raw := range state.peers {
+		peers[addr] = decodePeer(raw)
+	}
+	return peers
+}
+
+// Update fetches the changes to the peers of every watched torrent and applies them,
+// returning the resulting events. Torrents that no longer exist stop being watched
+// and all their peers are reported as disconnected
+func (w *PeerWatcher) Update() ([]Event, error) {
+	return w.UpdateCtx(context.Background())
+}
+
+// UpdateCtx is Update with a context that controls the lifetime of the request
+func (w *PeerWatcher) UpdateCtx(ctx context.Context) ([]Event, error) {
+	w.updateMu.Lock()
+	defer w.updateMu.Unlock()
+
+	var events []Event
+	for _, hash := range w.Hashes() {
+		w.mu.RLock()
+		state, ok := w.torrents[hash]
+		rid := 0
+		if ok {
+			rid = state.rid
+		}
+		w.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		update, err := fetchTorrentPeersUpdate(ctx, w.reader, hash, rid)
+		if errors.Is(err, ErrNotFound) {
+			events = append(events, w.drop(hash)...)
+			continue
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, w.apply(hash, update)...)
+	}
+	return events, nil
+}
+
+// Run polls until ctx is done or a request fails, calling handler for every event
+// in the order they were detected. It returns ctx.Err() when ctx is done
+func (w *PeerWatcher) Run(ctx context.Context, handler func(Event)) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		events, err := w.UpdateCtx(ctx)
+		for _, event := range events {
+			handler(event)
+		}
+		if err != nil {
+			return err
+		}
+		timer.Reset(interval)
+	}
+}
+
+// Events runs the watcher in the background and returns a channel receiving its events.
+// The channel is closed when ctx is done or a request fails; Err reports why
+func (w *PeerWatcher) Events(ctx context.Context) <-chan Event {
+	return streamEvents(ctx, w.Run, func(err error) {
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	})
+}
+
+// Err returns the error that closed the channel returned by Events
+func (w *PeerWatcher) Err() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.err
+}
+
+// apply merges an update into the peer set of a torrent and returns the resulting events
+func (w *PeerWatcher) apply(hash string, update torrentPeersUpdate) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.torrents[hash]
+	if !ok {
+		// removed while the request was in flight
+		return nil
+	}
+
+	var connected, disconnected []string
+	previous := state.peers
+	if update.FullUpdate {
+		state.peers = map[string]fields{}
+	} else {
+		state.peers = make(map[string]fields, len(previous))
+		for addr, raw := range previous {
+			state.peers[addr] = raw
+		}
+	}
+
+	for addr, changed := range update.Peers {
+		merged := fields{}
+		if old, ok := state.peers[addr]; ok {
+			merged.merge(old)
+		} else if _, ok := previous[addr]; !ok {
+			connected = append(connected, addr)
+		}
+		merged.merge(changed)
+		state.peers[addr] = merged
+	}
+	for _, addr := range update.PeersRemoved {
+		delete(state.peers, addr)
+	}
+	for addr := range previous {
+		if _, ok := state.peers[addr]; !ok {
+			disconnected = append(disconnected, addr)
+		}
+	}
+	state.rid = update.Rid
+
+	emit := state.synced || w.EmitExisting
+	state.synced = true
+	if !emit {
+		return nil
+	}
+
+	sort.Strings(connected)
+	sort.Strings(disconnected)
+	var events []Event
+	for _, addr := range connected {
+		events = append(events, PeerConnected{Hash: hash, Addr: addr, Peer: decodePeer(state.peers[addr])})
+	}
+	for _, addr := range disconnected {
+		events = append(events, PeerDisconnected{Hash: hash, Addr: addr, Peer: decodePeer(previous[addr])})
+	}
+	return events
+}
+
+// drop stops watching a torrent that no longer exists, disconnecting all its peers
+func (w *PeerWatcher) drop(hash string) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state, ok := w.torrents[hash]
+	if !ok {
+		return nil
+	}
+	delete(w.torrents, hash)
+
+	addrs := make([]string, 0, len(state.peers))
+	for addr := range state.peers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	var events []Event
+	for _, addr := range addrs {
+		events = append(events, PeerDisconnected{Hash: hash, Addr: addr, Peer: decodePeer(state.peers[addr])})
+	}
+	return events
+}
+
+// decodePeer converts merged peer fields into a TorrentPeer
+func decodePeer(raw fields) TorrentPeer {
+	var peer TorrentPeer
+	raw.decode(&peer)
+	return peer
+}
+
+// fetchTorrentPeersUpdate fetches sync/torrentPeers in the raw form PeerWatcher merges
+func fetchTorrentPeersUpdate(ctx context.Context, reader SyncReader, hash string, rid int) (update torrentPeersUpdate, err error) {
+	data, err := reader.RawTorrentPeersCtx(ctx, hash, strconv.Itoa(rid))
+	if err != nil {
+		return update, err
+	}
+	if err := json.Unmarshal(data, &update); err != nil {
+		return update, fmt.Errorf("failed to decode sync/torrentPeers: %w", err)
+	}
+	return update, nil
+}
@@ -0,0 +1,58 @@
+package qbt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+func TestPeerWatcherEvents(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	srv.AddTorrent(qbt.TorrentInfo{Hash: hashA, Name: "a"})
+	srv.SetPeers(hashA, map[string]qbt.TorrentPeer{"10.0.0.1:6881": {Client: "first"}})
+
+	watcher := qbt.NewPeerWatcher(client, hashA)
+	events, err := watcher.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("first update emitted %v for the existing peers", events)
+	}
+
+	srv.SetPeers(hashA, map[string]qbt.TorrentPeer{"10.0.0.2:6881": {Client: "second"}})
+	events, err = watcher.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []qbt.Event{
+		qbt.PeerConnected{Hash: hashA, Addr: "10.0.0.2:6881", Peer: qbt.TorrentPeer{Client: "second"}},
+		qbt.PeerDisconnected{Hash: hashA, Addr: "10.0.0.1:6881", Peer: qbt.TorrentPeer{Client: "first"}},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got %#v, want %#v", events, want)
+	}
+	if peers := watcher.Peers(hashA); len(peers) != 1 || peers["10.0.0.2:6881"].Client != "second" {
+		t.Errorf("peers are %v", peers)
+	}
+
+	if err := client.Delete([]string{hashA}, false); err != nil {
+		t.Fatal(err)
+	}
+	events, err = watcher.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []qbt.Event{qbt.PeerDisconnected{Hash: hashA, Addr: "10.0.0.2:6881", Peer: qbt.TorrentPeer{Client: "second"}}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got %#v after removing the torrent, want %#v", events, want)
+	}
+	if hashes := watcher.Hashes(); len(hashes) != 0 {
+		t.Errorf("still watching %v", hashes)
+	}
+}
@@ -0,0 +1,272 @@
+package qbt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPreference is returned for a preference value qbittorrent does not accept
+var ErrInvalidPreference = errors.New("invalid preference")
+
+// Values of Preferences.Encryption
+const (
+	EncryptionPrefer  = 0
+	EncryptionRequire = 1
+	EncryptionDisable = 2
+)
+
+// Values of Preferences.ProxyType
+const (
+	ProxyNone   = "None"
+	ProxyHTTP   = "HTTP"
+	ProxySOCKS5 = "SOCKS5"
+	ProxySOCKS4 = "SOCKS4"
+)
+
+// Values of proxy_type for qbittorrent before 4.6, which sends it as a number
+// and has separate values for proxies that need authentication.
+// Preferences.ProxyType holds them in decimal, e.g. "-1", and encodes them as numbers again
+const (
+	LegacyProxyNone       = -1
+	LegacyProxyHTTP       = 1
+	LegacyProxySOCKS5     = 2
+	LegacyProxyHTTPAuth   = 3
+	LegacyProxySOCKS5Auth = 4
+	LegacyProxySOCKS4     = 5
+)
+
+// Values of Preferences.DynDNSService
+const (
+	DynDNSServiceDynDNS = 0
+	DynDNSServiceNoIP   = 1
+)
+
+// Values of Preferences.MaxRatioAct
+const (
+	MaxRatioActStop            = 0
+	MaxRatioActRemove          = 1
+	MaxRatioActEnableSuperSeed = 2
+	MaxRatioActRemoveWithFiles = 3
+)
+
+// preferenceEnums lists the values qbittorrent accepts for enumerated preferences
+var preferenceEnums = map[string][]interface{}{
+	"encryption": {EncryptionPrefer, EncryptionRequire, EncryptionDisable},
+	"proxy_type": {
+		ProxyNone, ProxyHTTP, ProxySOCKS5, ProxySOCKS4,
+		LegacyProxyNone, LegacyProxyHTTP, LegacyProxySOCKS5, LegacyProxyHTTPAuth, LegacyProxySOCKS5Auth, LegacyProxySOCKS4,
+	},
+	"dyndns_service": {DynDNSServiceDynDNS, DynDNSServiceNoIP},
+	"max_ratio_act":  {MaxRatioActStop, MaxRatioActRemove, MaxRatioActEnableSuperSeed, MaxRatioActRemoveWithFiles},
+	// every day, weekdays, weekends, then monday to sunday
+	"scheduler_days": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+}
+
+// PreferencesPatch holds the preferences to change, by their JSON key, e.g.
+//
+//	qbt.PreferencesPatch{"dl_limit": 1024 * 1024, "proxy_type": qbt.ProxyNone}
+//
+// Preferences that are not in the patch are left unchanged by SetPreferences
+type PreferencesPatch map[string]interface{}
+
+// DiffPreferences returns the patch that changes from into to
+func DiffPreferences(from Preferences, to Preferences) (PreferencesPatch, error) {
+	fromFields, err := preferenceFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := preferenceFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := PreferencesPatch{}
+	for key, value := range toFields {
+		if old, ok := fromFields[key]; !ok || !bytes.Equal(old, value) {
+			patch[key] = value
+		}
+	}
+	return patch, nil
+}
+
+// Keys returns the keys of the patch in a stable order
+func (p PreferencesPatch) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the values of enumerated preferences against the ones qbittorrent accepts
+func (p PreferencesPatch) Validate() error {
+	for _, key := range p.Keys() {
+		allowed, ok := preferenceEnums[key]
+		if !ok {
+			continue
+		}
+
+		value, err := normalizeJSON(p[key])
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		valid := false
+		var names []string
+		for _, option := range allowed {
+			if normalized, _ := normalizeJSON(option); normalized == value {
+				valid = true
+				break
+			}
+			names = append(names, fmt.Sprintf("%#v", option))
+		}
+		if !valid {
+			return fmt.Errorf("%s is %#v, not one of %s: %w", key, value, strings.Join(names, ", "), ErrInvalidPreference)
+		}
+	}
+	return nil
+}
+
+// normalizeJSON converts v into the form it has when decoded from JSON, so values
+// compare equal regardless of their Go type or JSON formatting
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// preferenceKeys are the JSON keys of the preferences modeled by Preferences
+var preferenceKeys = jsonKeys(reflect.TypeOf(Preferences{}))
+
+// jsonKeys returns the JSON keys of the fields of a struct type
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// knownPreferences has the fields of Preferences without its JSON methods
+type knownPreferences Preferences
+
+// MarshalJSON encodes the preferences together with the Extra ones
+func (p Preferences) MarshalJSON() ([]byte, error) {
+	data, err := marshalWithExtra(knownPreferences(p), preferenceKeys, p.Extra)
+	if _, legacy := strconv.Atoi(p.ProxyType); err != nil || legacy != nil {
+		return data, err
+	}
+	return replaceField(data, "proxy_type", json.RawMessage(p.ProxyType))
+}
+
+// UnmarshalJSON decodes the preferences, keeping unknown keys in Extra
+func (p *Preferences) UnmarshalJSON(data []byte) error {
+	var known knownPreferences
+	if err := json.Unmarshal(data, &known); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || typeErr.Field != "proxy_type" {
+			return err
+		}
+		// older qbittorrent sends proxy_type as a number
+		var legacy struct {
+			ProxyType json.Number `json:"proxy_type"`
+		}
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return err
+		}
+		quoted, err := replaceField(data, "proxy_type", legacy.ProxyType.String())
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(quoted, &known); err != nil {
+			return err
+		}
+	}
+	extra, err := extraFields(data, preferenceKeys)
+	if err != nil {
+		return err
+	}
+	known.Extra = extra
+	*p = Preferences(known)
+	return nil
+}
+
+// marshalWithExtra encodes known, a struct without JSON methods, together with the
+// extra keys. Keys of the struct take precedence over stale copies in extra
+func marshalWithExtra(known interface{}, keys map[string]bool, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(known)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if !keys[key] {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// replaceField sets key of the JSON object in data to value
+func replaceField(data []byte, key string, value interface{}) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields[key] = encoded
+	return json.Marshal(fields)
+}
+
+// extraFields returns the fields of a JSON object that are not among keys, or nil if there are none
+func extraFields(data []byte, keys map[string]bool) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var extra map[string]json.RawMessage
+	for key, value := range fields {
+		if keys[key] {
+			continue
+		}
+		if extra == nil {
+			extra = map[string]json.RawMessage{}
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// preferenceFields returns the preferences by their JSON key
+func preferenceFields(prefs Preferences) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
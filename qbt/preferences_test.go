@@ -0,0 +1,134 @@
+package qbt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+func TestPreferencesExtraRoundTrip(t *testing.T) {
+	data := []byte(`{"dl_limit":1024,"future_preference":{"nested":[1,2]},"future_flag":true}`)
+
+	var prefs qbt.Preferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		t.Fatal(err)
+	}
+	if prefs.DlLimit != 1024 {
+		t.Errorf("dl_limit is %d, want 1024", prefs.DlLimit)
+	}
+	if len(prefs.Extra) != 2 || string(prefs.Extra["future_preference"]) != `{"nested":[1,2]}` {
+		t.Errorf("extra is %v, want the two unknown preferences", prefs.Extra)
+	}
+
+	// a stale copy of a modeled preference in Extra must not win over the field
+	prefs.Extra["dl_limit"] = json.RawMessage("1")
+	prefs.UlLimit = 2048
+	encoded, err := json.Marshal(prefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"dl_limit":          "1024",
+		"up_limit":          "2048",
+		"future_preference": `{"nested":[1,2]}`,
+		"future_flag":       "true",
+	} {
+		if got := string(fields[key]); got != want {
+			t.Errorf("%s is %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestPreferencesPatchValidate(t *testing.T) {
+	if err := (qbt.PreferencesPatch{"proxy_type": qbt.ProxySOCKS5, "encryption": 1}).Validate(); err != nil {
+		t.Errorf("valid patch: %v", err)
+	}
+	if err := (qbt.PreferencesPatch{"max_ratio_act": 7}).Validate(); !errors.Is(err, qbt.ErrInvalidPreference) {
+		t.Errorf("got %v, want ErrInvalidPreference", err)
+	}
+}
+
+func TestPreferencesPatchValidateProxyType(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		valid bool
+	}{
+		{qbt.ProxyHTTP, true},
+		{"None", true},
+		// older qbittorrent sends numbers, e.g. in a patch diffed from its preferences
+		{qbt.LegacyProxyNone, true},
+		{qbt.LegacyProxySOCKS5Auth, true},
+		{json.RawMessage("2"), true},
+		{"HTTPS", false},
+		{0, false},
+		{6, false},
+	} {
+		t.Run(fmt.Sprint(tc.value), func(t *testing.T) {
+			err := (qbt.PreferencesPatch{"proxy_type": tc.value}).Validate()
+			if tc.valid && err != nil {
+				t.Errorf("valid proxy_type: %v", err)
+			}
+			if !tc.valid && !errors.Is(err, qbt.ErrInvalidPreference) {
+				t.Errorf("got %v, want ErrInvalidPreference", err)
+			}
+		})
+	}
+}
+
+func TestPreferencesLegacyProxyType(t *testing.T) {
+	var prefs qbt.Preferences
+	if err := json.Unmarshal([]byte(`{"proxy_type":-1,"dl_limit":1024}`), &prefs); err != nil {
+		t.Fatal(err)
+	}
+	if prefs.ProxyType != "-1" || prefs.DlLimit != 1024 {
+		t.Fatalf("proxy_type is %q and dl_limit %d, want \"-1\" and 1024", prefs.ProxyType, prefs.DlLimit)
+	}
+
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["proxy_type"]) != "-1" {
+		t.Errorf("proxy_type is encoded as %s, want the number qbittorrent sent", fields["proxy_type"])
+	}
+
+	prefs.ProxyType = qbt.ProxySOCKS5
+	if data, err = json.Marshal(prefs); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["proxy_type"]) != `"SOCKS5"` {
+		t.Errorf("proxy_type is encoded as %s, want \"SOCKS5\"", fields["proxy_type"])
+	}
+}
+
+func TestSetPreferences(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if _, err := client.SetPreferences(qbt.PreferencesPatch{"dl_limit": 4096}); err != nil {
+		t.Fatal(err)
+	}
+	prefs, err := client.Preferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefs.DlLimit != 4096 || prefs.MaxActiveDls != 3 {
+		t.Errorf("dl_limit is %d and max_active_downloads %d, want 4096 and the default 3", prefs.DlLimit, prefs.MaxActiveDls)
+	}
+}
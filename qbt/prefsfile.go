@@ -0,0 +1,374 @@
+package qbt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// preferencesFileVersion is the version of the format written by ExportPreferences
+	preferencesFileVersion = 1
+
+	// redactedSecret replaces secrets that are exported without a reference
+	redactedSecret = "<redacted>"
+)
+
+// Formats of a preferences file
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// secretPreferences hold passwords and private keys and are never exported in clear text
+var secretPreferences = map[string]bool{
+	"web_ui_password":            true,
+	"proxy_password":             true,
+	"mail_notification_password": true,
+	"dyndns_password":            true,
+	"ssl_key":                    true,
+}
+
+// SecretPreferences returns the JSON keys of the preferences holding passwords and
+// private keys, in a stable order. Their values are never exported in clear text
+func SecretPreferences() []string {
+	keys := make([]string, 0, len(secretPreferences))
+	for key := range secretPreferences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// preferencesFile is the document written by ExportPreferences
+type preferencesFile struct {
+	Version     int                        `json:"version"`
+	Preferences map[string]json.RawMessage `json:"preferences"`
+}
+
+// yamlPreferencesFile is preferencesFile with the preferences decoded, as YAML cannot embed raw JSON
+type yamlPreferencesFile struct {
+	Version     int                    `yaml:"version"`
+	Preferences map[string]interface{} `yaml:"preferences"`
+}
+
+// ExportOptions control how ExportPreferences writes the file and its secrets
+type ExportOptions struct {
+	// Format is FormatJSON or FormatYAML, JSON if empty
+	Format string
+
+	// SecretRefs maps secret preferences to a reference written instead of their value,
+	// e.g. "proxy_password": "env:QBT_PROXY_PASSWORD". Secrets without a reference are
+	// written as "<redacted>" and left unchanged when the file is applied
+	SecretRefs map[string]string
+}
+
+// SecretResolver returns the value of a secret referenced from a preferences file
+type SecretResolver func(ref string) (string, error)
+
+// ResolveSecretRef resolves "env:NAME" to the environment variable NAME and "file:PATH"
+// to the contents of the file at PATH without surrounding whitespace
+func ResolveSecretRef(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(ref, "file:"):
+		data, err := os.ReadFile(strings.TrimPrefix(ref, "file:"))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	default:
+		return "", fmt.Errorf("unsupported secret reference %q", ref)
+	}
+}
+
+// ExportPreferences writes the preferences to w in the format of opts, with secrets
+// redacted or replaced by the references in opts
+func ExportPreferences(w io.Writer, prefs Preferences, opts ExportOptions) error {
+	fields, err := preferenceFields(prefs)
+	if err != nil {
+		return err
+	}
+
+	for key := range secretPreferences {
+		if _, ok := fields[key]; !ok {
+			continue
+		}
+		value := redactedSecret
+		if ref, ok := opts.SecretRefs[key]; ok {
+			value = ref
+		}
+		fields[key], _ = json.Marshal(value)
+	}
+
+	var data []byte
+	switch opts.Format {
+	case "", FormatJSON:
+		data, err = json.MarshalIndent(preferencesFile{Version: preferencesFileVersion, Preferences: fields}, "", "  ")
+		data = append(data, '\n')
+	case FormatYAML:
+		file := yamlPreferencesFile{Version: preferencesFileVersion, Preferences: map[string]interface{}{}}
+		for key, value := range fields {
+			if file.Preferences[key], err = yamlValue(value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		data, err = yaml.Marshal(file)
+	default:
+		return fmt.Errorf("unsupported preferences file format %q", opts.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// yamlValue decodes a JSON value into one that encodes the same in YAML,
+// keeping integers as integers instead of turning them into floats
+func yamlValue(data json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value), nil
+}
+
+// convertNumbers replaces the json.Numbers in a decoded JSON value by int64 or float64
+func convertNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	}
+	return value
+}
+
+// decodePreferencesFile reads a file written by ExportPreferences in either format.
+// YAML is a superset of JSON, but JSON is decoded as such to keep its numbers exact
+func decodePreferencesFile(r io.Reader) (file preferencesFile, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return file, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(data, &file)
+		return file, err
+	}
+
+	var yamlFile yamlPreferencesFile
+	if err := yaml.Unmarshal(data, &yamlFile); err != nil {
+		return file, err
+	}
+	file.Version = yamlFile.Version
+	file.Preferences = make(map[string]json.RawMessage, len(yamlFile.Preferences))
+	for key, value := range yamlFile.Preferences {
+		if file.Preferences[key], err = json.Marshal(value); err != nil {
+			return file, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return file, nil
+}
+
+// ImportPreferences reads preferences written by ExportPreferences in JSON or YAML as a patch.
+// Secret references are resolved with resolve, or ResolveSecretRef if nil;
+// redacted secrets are left out of the patch
+func ImportPreferences(r io.Reader, resolve SecretResolver) (PreferencesPatch, error) {
+	if resolve == nil {
+		resolve = ResolveSecretRef
+	}
+
+	file, err := decodePreferencesFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode preferences file: %w", err)
+	}
+	if file.Version != preferencesFileVersion {
+		return nil, fmt.Errorf("unsupported preferences file version %d", file.Version)
+	}
+
+	patch := PreferencesPatch{}
+	for key, value := range file.Preferences {
+		if !secretPreferences[key] {
+			patch[key] = value
+			continue
+		}
+
+		var ref string
+		if err := json.Unmarshal(value, &ref); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if ref == redactedSecret {
+			continue
+		}
+		secret, err := resolve(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		patch[key] = secret
+	}
+	return patch, nil
+}
+
+// PreferenceChange is a preference that differs, with its JSON values
+type PreferenceChange struct {
+	Key  string
+	From json.RawMessage // nil if the preference is missing
+	To   json.RawMessage
+}
+
+// String describes the change, hiding the values of secrets
+func (c PreferenceChange) String() string {
+	if secretPreferences[c.Key] {
+		return c.Key + ": " + redactedSecret + " (changed)"
+	}
+	from := "(missing)"
+	if c.From != nil {
+		from = string(c.From)
+	}
+	to := "(missing)"
+	if c.To != nil {
+		to = string(c.To)
+	}
+	return c.Key + ": " + from + " -> " + to
+}
+
+// PreferencesDiff lists the preferences that differ, ordered by key
+type PreferencesDiff []PreferenceChange
+
+// String describes the changes one per line
+func (d PreferencesDiff) String() string {
+	var b strings.Builder
+	for _, change := range d {
+		b.WriteString(change.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Patch returns the patch that makes the changes
+func (d PreferencesDiff) Patch() PreferencesPatch {
+	patch := PreferencesPatch{}
+	for _, change := range d {
+		if change.To != nil {
+			patch[change.Key] = change.To
+		}
+	}
+	return patch
+}
+
+// ComparePreferences returns the differences between the preferences of two instances
+func ComparePreferences(from Preferences, to Preferences) (PreferencesDiff, error) {
+	fromFields, err := preferenceFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := preferenceFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for key := range fromFields {
+		keys[key] = true
+	}
+	for key := range toFields {
+		keys[key] = true
+	}
+	return diffFields(fromFields, toFields, keys)
+}
+
+// DiffPatch returns the changes applying patch to an instance with prefs would make,
+// e.g. to preview a preferences file before it is applied. qbittorrent never reports
+// the WebUI password, so a password in the patch shows as a change
+func DiffPatch(prefs Preferences, patch PreferencesPatch) (PreferencesDiff, error) {
+	fields, err := preferenceFields(prefs)
+	if err != nil {
+		return nil, err
+	}
+
+	patchFields := map[string]json.RawMessage{}
+	keys := map[string]bool{}
+	for key, value := range patch {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		patchFields[key] = data
+		keys[key] = true
+	}
+
+	return diffFields(fields, patchFields, keys)
+}
+
+// ApplyPreferencesFile applies preferences written by ExportPreferences to an instance,
+// changing only the preferences that differ. It returns the changes it made
+func ApplyPreferencesFile(ctx context.Context, app AppController, r io.Reader, resolve SecretResolver) (PreferencesDiff, error) {
+	patch, err := ImportPreferences(r, resolve)
+	if err != nil {
+		return nil, err
+	}
+	current, err := app.PreferencesCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	diff, err := DiffPatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := app.SetPreferencesCtx(ctx, diff.Patch()); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// diffFields compares the given keys of two sets of preferences
+func diffFields(from map[string]json.RawMessage, to map[string]json.RawMessage, keys map[string]bool) (PreferencesDiff, error) {
+	var diff PreferencesDiff
+	for key := range keys {
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		if inFrom && inTo {
+			a, err := normalizeJSON(fromValue)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			b, err := normalizeJSON(toValue)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			if reflect.DeepEqual(a, b) {
+				continue
+			}
+		}
+		diff = append(diff, PreferenceChange{Key: key, From: fromValue, To: toValue})
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Key < diff[j].Key })
+	return diff, nil
+}
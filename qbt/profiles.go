@@ -0,0 +1,141 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrProfileNotApplied is returned when qbittorrent did not take a preference of a profile
+var ErrProfileNotApplied = errors.New("profile not applied")
+
+// writeOnlyPreferences are accepted by qbittorrent but never reported back
+var writeOnlyPreferences = map[string]bool{
+	"web_ui_password": true,
+}
+
+// PreferenceProfile is a named set of preferences that are switched to together,
+// e.g. a "night" profile lifting the speed limits
+type PreferenceProfile struct {
+	Name        string
+	Preferences PreferencesPatch
+}
+
+// NewPreferenceProfile returns a profile setting the preferences with the given JSON keys
+// to their values in prefs, so profiles can be written with the typed Preferences:
+//
+//	night, err := qbt.NewPreferenceProfile("night", qbt.Preferences{MaxActiveDls: 10}, "dl_limit", "max_active_downloads")
+func NewPreferenceProfile(name string, prefs Preferences, keys ...string) (PreferenceProfile, error) {
+	fields, err := preferenceFields(prefs)
+	if err != nil {
+		return PreferenceProfile{}, err
+	}
+
+	profile := PreferenceProfile{Name: name, Preferences: PreferencesPatch{}}
+	for _, key := range keys {
+		value, ok := fields[key]
+		if !ok {
+			return PreferenceProfile{}, fmt.Errorf("profile %s: unknown preference %s", name, key)
+		}
+		profile.Preferences[key] = value
+	}
+	return profile, nil
+}
+
+// AppliedProfile is a profile that was applied, with the values it replaced
+type AppliedProfile struct {
+	Profile PreferenceProfile
+	// Previous holds the values the profile replaced. Write-only preferences such as
+	// web_ui_password are not reported by qbittorrent and can't be restored
+	Previous PreferencesPatch
+
+	app AppController
+}
+
+// Apply switches qbittorrent to the profile. The current values of the preferences it
+// changes are captured first; if setting or verifying the new values fails they are
+// restored before the error is returned
+func (p PreferenceProfile) Apply(ctx context.Context, app AppController) (*AppliedProfile, error) {
+	if err := p.Preferences.Validate(); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", p.Name, err)
+	}
+
+	fields, err := reportedPreferences(ctx, app)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", p.Name, err)
+	}
+
+	applied := &AppliedProfile{Profile: p, Previous: PreferencesPatch{}, app: app}
+	for key := range p.Preferences {
+		if value, ok := fields[key]; ok && !writeOnlyPreferences[key] {
+			applied.Previous[key] = value
+		}
+	}
+
+	if _, err := app.SetPreferencesCtx(ctx, p.Preferences); err != nil {
+		return nil, applied.fail(ctx, fmt.Errorf("profile %s: %w", p.Name, err))
+	}
+	if err := applied.verify(ctx); err != nil {
+		return nil, applied.fail(ctx, err)
+	}
+	return applied, nil
+}
+
+// Rollback restores the values the profile replaced
+func (a *AppliedProfile) Rollback(ctx context.Context) error {
+	if _, err := a.app.SetPreferencesCtx(ctx, a.Previous); err != nil {
+		return fmt.Errorf("profile %s: rollback failed: %w", a.Profile.Name, err)
+	}
+	return nil
+}
+
+// verify checks that qbittorrent reports the values of the profile
+func (a *AppliedProfile) verify(ctx context.Context) error {
+	fields, err := reportedPreferences(ctx, a.app)
+	if err != nil {
+		return fmt.Errorf("profile %s: %w", a.Profile.Name, err)
+	}
+
+	for _, key := range a.Profile.Preferences.Keys() {
+		if _, captured := a.Previous[key]; !captured {
+			// write-only or unknown to this qbittorrent, so there is nothing to compare
+			continue
+		}
+		want, err := normalizeJSON(a.Profile.Preferences[key])
+		if err != nil {
+			return err
+		}
+		got, err := normalizeJSON(fields[key])
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(want, got) {
+			return fmt.Errorf("profile %s: %s is %v instead of %v: %w", a.Profile.Name, key, got, want, ErrProfileNotApplied)
+		}
+	}
+	return nil
+}
+
+// reportedPreferences returns the preferences qbittorrent reports by their JSON key.
+// Unlike the fields of Preferences, they leave out what the release does not know
+func reportedPreferences(ctx context.Context, app AppController) (map[string]json.RawMessage, error) {
+	data, err := app.RawPreferencesCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode preferences: %w", err)
+	}
+	return fields, nil
+}
+
+// fail rolls back after err, reporting both errors if the rollback fails as well
+func (a *AppliedProfile) fail(ctx context.Context, err error) error {
+	if rollbackErr := a.Rollback(ctx); rollbackErr != nil {
+		return fmt.Errorf("%w (%v)", err, rollbackErr)
+	}
+	return err
+}
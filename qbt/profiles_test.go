@@ -0,0 +1,112 @@
+package qbt_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// limitedRelease is a client for a qbittorrent that does not know some preferences
+// and refuses to change others
+type limitedRelease struct {
+	*qbt.Client
+	unknown  map[string]bool // neither reported nor set
+	readOnly map[string]bool // reported but not set
+}
+
+func (r limitedRelease) RawPreferencesCtx(ctx context.Context) (json.RawMessage, error) {
+	data, err := r.Client.RawPreferencesCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key := range r.unknown {
+		delete(fields, key)
+	}
+	return json.Marshal(fields)
+}
+
+func (r limitedRelease) SetPreferencesCtx(ctx context.Context, patch qbt.PreferencesPatch) (bool, error) {
+	taken := qbt.PreferencesPatch{}
+	for key, value := range patch {
+		if !r.unknown[key] && !r.readOnly[key] {
+			taken[key] = value
+		}
+	}
+	return r.Client.SetPreferencesCtx(ctx, taken)
+}
+
+// nightProfile lifts the download limit and allows more active downloads
+var nightProfile = qbt.PreferenceProfile{
+	Name:        "night",
+	Preferences: qbt.PreferencesPatch{"dl_limit": 0, "max_active_downloads": 10},
+}
+
+func TestProfileApplyAndRollback(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if _, err := client.SetPreferences(qbt.PreferencesPatch{"dl_limit": 1024}); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := nightProfile.Apply(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefs, _ := client.Preferences()
+	if prefs.DlLimit != 0 || prefs.MaxActiveDls != 10 {
+		t.Errorf("applied profile left dl_limit %d and max_active_downloads %d", prefs.DlLimit, prefs.MaxActiveDls)
+	}
+
+	if err := applied.Rollback(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	prefs, _ = client.Preferences()
+	if prefs.DlLimit != 1024 || prefs.MaxActiveDls != 3 {
+		t.Errorf("rollback left dl_limit %d and max_active_downloads %d, want 1024 and 3", prefs.DlLimit, prefs.MaxActiveDls)
+	}
+}
+
+func TestProfileRollbackWhenNotApplied(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if _, err := client.SetPreferences(qbt.PreferencesPatch{"dl_limit": 1024}); err != nil {
+		t.Fatal(err)
+	}
+	app := limitedRelease{Client: client, readOnly: map[string]bool{"max_active_downloads": true}}
+
+	if _, err := nightProfile.Apply(context.Background(), app); !errors.Is(err, qbt.ErrProfileNotApplied) {
+		t.Fatalf("got %v, want ErrProfileNotApplied", err)
+	}
+	prefs, _ := client.Preferences()
+	if prefs.DlLimit != 1024 {
+		t.Errorf("dl_limit is %d after the failed profile, want it rolled back to 1024", prefs.DlLimit)
+	}
+}
+
+func TestProfileUnknownPreferences(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	app := limitedRelease{Client: srv.Client(), unknown: map[string]bool{"max_active_downloads": true}}
+
+	// a preference the release does not report can be neither verified nor restored
+	applied, err := nightProfile.Apply(context.Background(), app)
+	if err != nil {
+		t.Fatalf("profile with a preference unknown to the release: %v", err)
+	}
+	if _, ok := applied.Previous["max_active_downloads"]; ok || len(applied.Previous) != 1 {
+		t.Errorf("captured %v, want dl_limit only", applied.Previous)
+	}
+}
@@ -0,0 +1,639 @@
+package qbttest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// Web API versions that added or removed endpoints the fake implements
+var (
+	apiV2_1_0  = qbt.APIVersion{Major: 2, Minor: 1}
+	apiV2_1_1  = qbt.APIVersion{Major: 2, Minor: 1, Patch: 1}
+	apiV2_2_0  = qbt.APIVersion{Major: 2, Minor: 2}
+	apiV2_3_0  = qbt.APIVersion{Major: 2, Minor: 3}
+	apiV2_5_1  = qbt.APIVersion{Major: 2, Minor: 5, Patch: 1}
+	apiV2_9_1  = qbt.APIVersion{Major: 2, Minor: 9, Patch: 1}
+	apiV2_11_0 = qbt.APIVersion{Major: 2, Minor: 11}
+)
+
+// routes maps endpoints relative to api/v2/ to their handlers
+var routes = map[string]route{
+	"auth/login":  {method: http.MethodPost, handler: (*Server).handleLogin},
+	"auth/logout": {method: http.MethodPost, handler: (*Server).handleLogout},
+
+	"app/version":         {method: http.MethodGet, handler: (*Server).handleVersion},
+	"app/webapiVersion":   {method: http.MethodGet, handler: (*Server).handleWebAPIVersion},
+	"app/buildInfo":       {method: http.MethodGet, handler: (*Server).handleBuildInfo},
+	"app/preferences":     {method: http.MethodGet, handler: (*Server).handlePreferences},
+	"app/setPreferences":  {method: http.MethodPost, handler: (*Server).handleSetPreferences},
+	"app/defaultSavePath": {method: http.MethodGet, handler: (*Server).handleDefaultSavePath},
+	"app/shutdown":        {method: http.MethodPost, handler: (*Server).handleOK},
+
+	"log/main":  {method: http.MethodGet, handler: (*Server).handleEmptyList},
+	"log/peers": {method: http.MethodGet, handler: (*Server).handleEmptyList},
+
+	"sync/maindata":     {method: http.MethodGet, handler: (*Server).handleMainData},
+	"sync/torrentPeers": {method: http.MethodGet, handler: (*Server).handleTorrentPeers},
+
+	"transfer/info":                  {method: http.MethodGet, handler: (*Server).handleTransferInfo},
+	"transfer/speedLimitsMode":       {method: http.MethodGet, handler: (*Server).handleSpeedLimitsMode},
+	"transfer/toggleSpeedLimitsMode": {method: http.MethodPost, handler: (*Server).handleToggleSpeedLimitsMode},
+	"transfer/downloadLimit":         {method: http.MethodGet, handler: (*Server).handleDownloadLimit},
+	"transfer/setDownloadLimit":      {method: http.MethodPost, handler: (*Server).handleSetDownloadLimit},
+	"transfer/uploadLimit":           {method: http.MethodGet, handler: (*Server).handleUploadLimit},
+	"transfer/setUploadLimit":        {method: http.MethodPost, handler: (*Server).handleSetUploadLimit},
+	"transfer/banPeers":              {method: http.MethodPost, handler: (*Server).handleOK, since: apiV2_3_0},
+
+	"torrents/info":        {method: http.MethodGet, handler: (*Server).handleTorrents},
+	"torrents/properties":  {method: http.MethodGet, handler: (*Server).handleProperties},
+	"torrents/trackers":    {method: http.MethodGet, handler: (*Server).handleTrackers},
+	"torrents/webseeds":    {method: http.MethodGet, handler: (*Server).handleTorrentList},
+	"torrents/files":       {method: http.MethodGet, handler: (*Server).handleTorrentList},
+	"torrents/add":         {method: http.MethodPost, handler: (*Server).handleAdd},
+	"torrents/stop":        {method: http.MethodPost, handler: (*Server).handleStop, since: apiV2_11_0},
+	"torrents/start":       {method: http.MethodPost, handler: (*Server).handleStart, since: apiV2_11_0},
+	"torrents/pause":       {method: http.MethodPost, handler: (*Server).handleStop, until: apiV2_11_0},
+	"torrents/resume":      {method: http.MethodPost, handler: (*Server).handleStart, until: apiV2_11_0},
+	"torrents/delete":      {method: http.MethodPost, handler: (*Server).handleDelete},
+	"torrents/recheck":     {method: http.MethodPost, handler: (*Server).handleOK},
+	"torrents/reannounce":  {method: http.MethodPost, handler: (*Server).handleOK},
+	"torrents/addTrackers": {method: http.MethodPost, handler: (*Server).handleAddTrackers},
+
+	"torrents/categories":       {method: http.MethodGet, handler: (*Server).handleCategories},
+	"torrents/createCategory":   {method: http.MethodPost, handler: (*Server).handleCreateCategory},
+	"torrents/editCategory":     {method: http.MethodPost, handler: (*Server).handleEditCategory, since: apiV2_1_0},
+	"torrents/removeCategories": {method: http.MethodPost, handler: (*Server).handleRemoveCategories},
+	"torrents/setCategory":      {method: http.MethodPost, handler: (*Server).handleSetCategory},
+
+	"torrents/tags":       {method: http.MethodGet, handler: (*Server).handleTags, since: apiV2_3_0},
+	"torrents/createTags": {method: http.MethodPost, handler: (*Server).handleCreateTags, since: apiV2_3_0},
+	"torrents/deleteTags": {method: http.MethodPost, handler: (*Server).handleDeleteTags, since: apiV2_3_0},
+	"torrents/addTags":    {method: http.MethodPost, handler: (*Server).handleAddTags, since: apiV2_3_0},
+	"torrents/removeTags": {method: http.MethodPost, handler: (*Server).handleRemoveTags, since: apiV2_3_0},
+
+	"rss/addFolder":   {method: http.MethodPost, handler: (*Server).handleRSSAddFolder},
+	"rss/addFeed":     {method: http.MethodPost, handler: (*Server).handleRSSAddFeed},
+	"rss/removeItem":  {method: http.MethodPost, handler: (*Server).handleRSSRemoveItem},
+	"rss/moveItem":    {method: http.MethodPost, handler: (*Server).handleRSSMoveItem},
+	"rss/items":       {method: http.MethodGet, handler: (*Server).handleRSSItems},
+	"rss/markAsRead":  {method: http.MethodPost, handler: (*Server).handleRSSMarkAsRead, since: apiV2_5_1},
+	"rss/refreshItem": {method: http.MethodPost, handler: (*Server).handleRSSRefreshItem, since: apiV2_2_0},
+	"rss/setFeedURL":  {method: http.MethodPost, handler: (*Server).handleRSSSetFeedURL, since: apiV2_9_1},
+
+	"rss/setRule":          {method: http.MethodPost, handler: (*Server).handleRSSSetRule},
+	"rss/renameRule":       {method: http.MethodPost, handler: (*Server).handleRSSRenameRule},
+	"rss/removeRule":       {method: http.MethodPost, handler: (*Server).handleRSSRemoveRule},
+	"rss/rules":            {method: http.MethodGet, handler: (*Server).handleRSSRules},
+	"rss/matchingArticles": {method: http.MethodGet, handler: (*Server).handleRSSMatchingArticles, since: apiV2_5_1},
+
+	"search/start":   {method: http.MethodPost, handler: (*Server).handleSearchStart, since: apiV2_1_1},
+	"search/stop":    {method: http.MethodPost, handler: (*Server).handleSearchStop, since: apiV2_1_1},
+	"search/status":  {method: http.MethodGet, handler: (*Server).handleSearchStatus, since: apiV2_1_1},
+	"search/results": {method: http.MethodGet, handler: (*Server).handleSearchResults, since: apiV2_1_1},
+	"search/delete":  {method: http.MethodPost, handler: (*Server).handleSearchDelete, since: apiV2_1_1},
+
+	"search/plugins":         {method: http.MethodGet, handler: (*Server).handleSearchPlugins, since: apiV2_1_1},
+	"search/installPlugin":   {method: http.MethodPost, handler: (*Server).handleSearchInstallPlugin, since: apiV2_1_1},
+	"search/uninstallPlugin": {method: http.MethodPost, handler: (*Server).handleSearchUninstallPlugin, since: apiV2_1_1},
+	"search/enablePlugin":    {method: http.MethodPost, handler: (*Server).handleSearchEnablePlugin, since: apiV2_1_1},
+	"search/updatePlugins":   {method: http.MethodPost, handler: (*Server).handleSearchUpdatePlugins, since: apiV2_1_1},
+}
+
+// handleOK accepts a request without changing any state
+func (s *Server) handleOK(w http.ResponseWriter, r *http.Request) {}
+
+// handleEmptyList answers with an empty JSON list
+func (s *Server) handleEmptyList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, []interface{}{})
+}
+
+// Auth endpoints
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Form.Get("username") != s.username || r.Form.Get("password") != s.password {
+		writeText(w, "Fails.")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: cookieSID, Value: s.newSession(), Path: "/", HttpOnly: true})
+	writeText(w, "Ok.")
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(cookieSID); err == nil {
+		delete(s.sessions, cookie.Value)
+	}
+}
+
+// Application endpoints
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if s.webAPIVersion.AtLeast(apiV2_11_0) {
+		writeText(w, "v5.0.5")
+	} else {
+		writeText(w, "v4.6.7")
+	}
+}
+
+func (s *Server) handleWebAPIVersion(w http.ResponseWriter, r *http.Request) {
+	writeText(w, s.webAPIVersion.String())
+}
+
+func (s *Server) handleBuildInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, qbt.BuildInfo{
+		QTVersion:         "6.7.3",
+		LibtorrentVersion: "2.0.11.0",
+		BoostVersion:      "1.86.0",
+		OpenSSLVersion:    "3.4.1",
+		AppBitness:        64,
+	})
+}
+
+func (s *Server) handlePreferences(w http.ResponseWriter, r *http.Request) {
+	prefs := map[string]json.RawMessage{}
+	for key, value := range s.preferences {
+		// qbittorrent accepts the password but never reports it
+		if key != "web_ui_password" {
+			prefs[key] = value
+		}
+	}
+	writeJSON(w, prefs)
+}
+
+func (s *Server) handleSetPreferences(w http.ResponseWriter, r *http.Request) {
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(r.Form.Get("json")), &changes); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	// qbittorrent silently ignores keys it doesn't know
+	for key, value := range changes {
+		if _, ok := s.preferences[key]; ok {
+			s.preferences[key] = value
+		}
+	}
+}
+
+func (s *Server) handleDefaultSavePath(w http.ResponseWriter, r *http.Request) {
+	var savePath string
+	json.Unmarshal(s.preferences["save_path"], &savePath)
+	writeText(w, savePath)
+}
+
+// Transfer endpoints
+
+func (s *Server) handleTransferInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.serverState())
+}
+
+func (s *Server) handleSpeedLimitsMode(w http.ResponseWriter, r *http.Request) {
+	if s.transfer.altSpeed {
+		writeText(w, "1")
+	} else {
+		writeText(w, "0")
+	}
+}
+
+func (s *Server) handleToggleSpeedLimitsMode(w http.ResponseWriter, r *http.Request) {
+	s.transfer.altSpeed = !s.transfer.altSpeed
+}
+
+func (s *Server) handleDownloadLimit(w http.ResponseWriter, r *http.Request) {
+	writeText(w, strconv.Itoa(s.transfer.dlLimit))
+}
+
+func (s *Server) handleSetDownloadLimit(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.Form.Get("limit"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	s.transfer.dlLimit = limit
+}
+
+func (s *Server) handleUploadLimit(w http.ResponseWriter, r *http.Request) {
+	writeText(w, strconv.Itoa(s.transfer.ulLimit))
+}
+
+func (s *Server) handleSetUploadLimit(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.Form.Get("limit"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	s.transfer.ulLimit = limit
+}
+
+// Torrent endpoints
+
+func (s *Server) handleTorrents(w http.ResponseWriter, r *http.Request) {
+	filter := r.Form.Get("filter")
+	category, filterCategory := r.Form["category"]
+	tag, filterTag := r.Form["tag"]
+	hashes := map[string]bool{}
+	for _, hash := range splitList(r.Form.Get("hashes"), "|") {
+		hashes[strings.ToLower(hash)] = true
+	}
+
+	list := []qbt.TorrentInfo{}
+	for _, t := range s.torrents {
+		if len(hashes) > 0 && !hashes[t.info.Hash] {
+			continue
+		}
+		if filterCategory && t.info.Category != category[0] {
+			continue
+		}
+		if filterTag && !(tag[0] == "" && len(t.tags) == 0) && !t.hasTag(tag[0]) {
+			continue
+		}
+		if !s.matchesFilter(t.info, filter) {
+			continue
+		}
+		list = append(list, t.info)
+	}
+
+	sortTorrents(list, r.Form.Get("sort"), r.Form.Get("reverse") == "true")
+
+	if offset, err := strconv.Atoi(r.Form.Get("offset")); err == nil {
+		if offset < 0 {
+			offset += len(list)
+		}
+		if offset < 0 {
+			offset = 0
+		}
+		if offset > len(list) {
+			offset = len(list)
+		}
+		list = list[offset:]
+	}
+	if limit, err := strconv.Atoi(r.Form.Get("limit")); err == nil && limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+
+	writeJSON(w, list)
+}
+
+// matchesFilter implements the filter parameter of torrents/info
+func (s *Server) matchesFilter(info qbt.TorrentInfo, filter string) bool {
+	stopped := s.isStopped(info.State)
+	complete := info.Progress >= 1
+	switch filter {
+	case "", "all":
+		return true
+	case "downloading":
+		return !complete && !stopped
+	case "seeding":
+		return complete && !stopped
+	case "completed":
+		return complete
+	case "stopped", "paused":
+		return stopped
+	case "running", "resumed":
+		return !stopped
+	case "active":
+		return info.Dlspeed > 0 || info.Upspeed > 0
+	case "inactive":
+		return info.Dlspeed == 0 && info.Upspeed == 0
+	case "errored":
+		return info.State == "error" || info.State == "missingFiles"
+	default:
+		return true
+	}
+}
+
+// sortTorrents sorts torrents by the JSON field named key
+func sortTorrents(list []qbt.TorrentInfo, key string, reverse bool) {
+	if key == "" {
+		sort.Slice(list, func(i, j int) bool { return list[i].Hash < list[j].Hash })
+		return
+	}
+
+	fields := make([]map[string]interface{}, len(list))
+	for i, info := range list {
+		fields[i] = toFields(info)
+	}
+	less := func(a, b interface{}) bool {
+		switch a := a.(type) {
+		case float64:
+			b, _ := b.(float64)
+			return a < b
+		case string:
+			b, _ := b.(string)
+			return a < b
+		case bool:
+			b, _ := b.(bool)
+			return !a && b
+		}
+		return false
+	}
+
+	indexes := make([]int, len(list))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := fields[indexes[i]][key], fields[indexes[j]][key]
+		if reverse {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+
+	sorted := make([]qbt.TorrentInfo, len(list))
+	for i, index := range indexes {
+		sorted[i] = list[index]
+	}
+	copy(list, sorted)
+}
+
+func (s *Server) handleProperties(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.torrents[strings.ToLower(r.Form.Get("hash"))]
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	var eta int
+	if t.info.Eta > 0 {
+		eta = int(t.info.Eta)
+	}
+	writeJSON(w, qbt.Torrent{
+		AdditionDate:   int(t.info.AddedOn),
+		CompletionDate: int(t.info.CompletionOn),
+		DlLimit:        int(t.info.DlLimit),
+		DlSpeed:        int(t.info.Dlspeed),
+		Eta:            eta,
+		SavePath:       t.info.SavePath,
+		SeedingTime:    int(t.info.TimeActive),
+		Seeds:          int(t.info.NumSeeds),
+		Peers:          int(t.info.NumLeechs),
+		ShareRatio:     t.info.Ratio,
+		TimeElapsed:    int(t.info.TimeActive),
+		TotalDl:        int(t.info.Downloaded),
+		TotalSize:      int(t.info.TotalSize),
+		TotalUl:        int(t.info.Uploaded),
+		UpLimit:        int(t.info.UpLimit),
+		UpSpeed:        int(t.info.Upspeed),
+	})
+}
+
+func (s *Server) handleTrackers(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.torrents[strings.ToLower(r.Form.Get("hash"))]
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	trackers := t.trackers
+	if trackers == nil {
+		trackers = []qbt.Tracker{}
+	}
+	writeJSON(w, trackers)
+}
+
+// handleTorrentList answers with an empty list for an existing torrent, for details the fake does not model
+func (s *Server) handleTorrentList(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.torrents[strings.ToLower(r.Form.Get("hash"))]; !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, []interface{}{})
+}
+
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	var added []*torrent
+	for _, link := range splitList(r.FormValue("urls"), "\n") {
+		if t, ok := torrentFromLink(link); ok {
+			added = append(added, t)
+		}
+	}
+	if r.MultipartForm != nil {
+		for _, header := range r.MultipartForm.File["torrents"] {
+			file, err := header.Open()
+			if err != nil {
+				continue
+			}
+			contents, _ := io.ReadAll(file)
+			file.Close()
+			if t, ok := torrentFromFile(header.Filename, contents); ok {
+				added = append(added, t)
+			}
+		}
+	}
+	if len(added) == 0 {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		writeText(w, "Fails.")
+		return
+	}
+
+	// qbittorrent 5 renamed the paused parameter to stopped
+	stopped := r.FormValue("paused") == "true" || r.FormValue("stopped") == "true"
+	category := r.FormValue("category")
+	if category != "" {
+		if _, ok := s.categories[category]; !ok {
+			s.categories[category] = qbt.Category{Name: category}
+		}
+	}
+	tags := splitList(r.FormValue("tags"), ",")
+	for _, tag := range tags {
+		s.tags[tag] = true
+	}
+	savePath := r.FormValue("savepath")
+	if savePath == "" {
+		json.Unmarshal(s.preferences["save_path"], &savePath)
+	}
+
+	for _, t := range added {
+		if _, exists := s.torrents[t.info.Hash]; exists {
+			continue
+		}
+		if rename := r.FormValue("rename"); rename != "" {
+			t.info.Name = rename
+		}
+		t.info.Category = category
+		t.info.SavePath = savePath
+		t.info.AddedOn = time.Now().Unix()
+		t.info.AmountLeft = t.info.Size
+		t.info.TotalSize = t.info.Size
+		t.info.DlLimit, _ = strconv.ParseInt(r.FormValue("dlLimit"), 10, 64)
+		t.info.UpLimit, _ = strconv.ParseInt(r.FormValue("upLimit"), 10, 64)
+		t.info.SeqDl = r.FormValue("sequentialDownload") == "true"
+		t.info.FLPiecePrio = r.FormValue("firstLastPiecePrio") == "true"
+		t.info.AutoTmm = r.FormValue("autoTMM") == "true"
+		t.info.TrackersCount = int64(len(t.trackers))
+		if len(t.trackers) > 0 {
+			t.info.Tracker = t.trackers[0].URL
+		}
+		if stopped {
+			t.info.State = s.stoppedState(0)
+		} else {
+			t.info.State = s.runningState(0)
+		}
+		t.setTags(append([]string(nil), tags...))
+		s.torrents[t.info.Hash] = t
+	}
+	writeText(w, "Ok.")
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	for _, t := range s.selectTorrents(r.Form.Get("hashes")) {
+		t.info.State = s.stoppedState(t.info.Progress)
+		t.info.Dlspeed = 0
+		t.info.Upspeed = 0
+	}
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	for _, t := range s.selectTorrents(r.Form.Get("hashes")) {
+		if s.isStopped(t.info.State) {
+			t.info.State = s.runningState(t.info.Progress)
+		}
+	}
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	for _, t := range s.selectTorrents(r.Form.Get("hashes")) {
+		delete(s.torrents, t.info.Hash)
+	}
+}
+
+func (s *Server) handleAddTrackers(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.torrents[strings.ToLower(r.Form.Get("hash"))]
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	for _, tracker := range splitList(r.Form.Get("urls"), "\n") {
+		t.trackers = append(t.trackers, qbt.Tracker{URL: tracker, Tier: len(t.trackers), Status: 1})
+	}
+	t.info.TrackersCount = int64(len(t.trackers))
+	if t.info.Tracker == "" && len(t.trackers) > 0 {
+		t.info.Tracker = t.trackers[0].URL
+	}
+}
+
+// Category endpoints
+
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.categories)
+}
+
+func (s *Server) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	name := r.Form.Get("category")
+	if name == "" {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if _, exists := s.categories[name]; exists || strings.Contains(name, "\\") {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	s.categories[name] = qbt.Category{Name: name, SavePath: r.Form.Get("savePath")}
+}
+
+func (s *Server) handleEditCategory(w http.ResponseWriter, r *http.Request) {
+	name := r.Form.Get("category")
+	if name == "" {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if _, exists := s.categories[name]; !exists {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	s.categories[name] = qbt.Category{Name: name, SavePath: r.Form.Get("savePath")}
+}
+
+func (s *Server) handleRemoveCategories(w http.ResponseWriter, r *http.Request) {
+	for _, name := range splitList(r.Form.Get("categories"), "\n") {
+		delete(s.categories, name)
+		for _, t := range s.torrents {
+			if t.info.Category == name {
+				t.info.Category = ""
+			}
+		}
+	}
+}
+
+func (s *Server) handleSetCategory(w http.ResponseWriter, r *http.Request) {
+	category := r.Form.Get("category")
+	if _, exists := s.categories[category]; category != "" && !exists {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	for _, t := range s.selectTorrents(r.Form.Get("hashes")) {
+		t.info.Category = category
+	}
+}
+
+// Tag endpoints
+
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.tagList())
+}
+
+// tagList returns the sorted names of all tags
+func (s *Server) tagList() []string {
+	tags := []string{}
+	for tag := range s.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func (s *Server) handleCreateTags(w http.ResponseWriter, r *http.Request) {
+	for _, tag := range splitList(r.Form.Get("tags"), ",") {
+		s.tags[tag] = true
+	}
+}
+
+func (s *Server) handleDeleteTags(w http.ResponseWriter, r *http.Request) {
+	for _, tag := range splitList(r.Form.Get("tags"), ",") {
+		delete(s.tags, tag)
+		for _, t := range s.torrents {
+			t.setTags(removeTag(t.tags, tag))
+		}
+	}
+}
+
+func (s *Server) handleAddTags(w http.ResponseWriter, r *http.Request) {
+	tags := splitList(r.Form.Get("tags"), ",")
+	for _, t := range s.selectTorrents(r.Form.Get("hashes")) {
+		for _, tag := range tags {
+			s.tags[tag] = true
+			if !t.hasTag(tag) {
+				t.setTags(append(t.tags, tag))
+			}
+		}
+	}
+}
+
+func (s *Server) handleRemoveTags(w http.ResponseWriter, r *http.Request) {
+	tags := splitList(r.Form.Get("tags"), ",")
+	for _, t := range s.selectTorrents(r.Form.Get("hashes")) {
+		// an empty list removes all tags from the torrents
+		if len(tags) == 0 {
+			t.setTags(nil)
+		}
+		for _, tag := range tags {
+			t.setTags(removeTag(t.tags, tag))
+		}
+	}
+}
+
+// removeTag returns tags without tag
+func removeTag(tags []string, tag string) []string {
+	var kept []string
+	for _, existing := range tags {
+		if existing != tag {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,364 @@
+package qbttest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// scrubbed replaces secrets in recorded fixtures
+const scrubbed = "SCRUBBED"
+
+// secretKeys are parameters and JSON fields that hold secrets: the password of a
+// login and the secret preferences
+var secretKeys = func() map[string]bool {
+	keys := map[string]bool{"password": true}
+	for _, key := range qbt.SecretPreferences() {
+		keys[key] = true
+	}
+	return keys
+}()
+
+// ErrNoInteraction is returned by a Replayer for requests that were not recorded
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Fixture is a recorded sequence of requests to a qbittorrent instance
+type Fixture struct {
+	QBittorrentVersion string        `json:"qbittorrent_version,omitempty"`
+	Interactions       []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and the response qbittorrent gave
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request that identifies it during replay
+type RecordedRequest struct {
+	Method string     `json:"method"`
+	Path   string     `json:"path"`
+	Params url.Values `json:"params,omitempty"` // query and form parameters, files as name:sha256
+}
+
+// RecordedResponse is a response as qbittorrent sent it, with secrets scrubbed
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// LoadFixture reads a fixture written by Recorder.Save
+func LoadFixture(fileName string) (*Fixture, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture: %w", err)
+	}
+	return &fixture, nil
+}
+
+// Recorder is an http.RoundTripper that records the requests sent through it and the
+// responses received. Session cookies and passwords are scrubbed from the recording.
+// Use it with qbt.WithTransport against a real qbittorrent to capture a fixture
+type Recorder struct {
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder returns a Recorder sending requests through transport, or http.DefaultTransport if nil
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport}
+}
+
+// RoundTrip sends the request and records it along with the response
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, body, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := rec.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := http.Header{}
+	for _, key := range []string{"Content-Type", "Set-Cookie"} {
+		for _, value := range resp.Header.Values(key) {
+			header.Add(key, value)
+		}
+	}
+	if cookies := header.Values("Set-Cookie"); len(cookies) > 0 {
+		header.Del("Set-Cookie")
+		for _, cookie := range cookies {
+			header.Add("Set-Cookie", scrubCookie(cookie))
+		}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if strings.HasSuffix(recorded.Path, "app/version") && resp.StatusCode == http.StatusOK {
+		rec.fixture.QBittorrentVersion = string(respBody)
+	}
+	rec.fixture.Interactions = append(rec.fixture.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       scrubBody(respBody),
+		},
+	})
+	return resp, nil
+}
+
+// Fixture returns a copy of everything recorded so far
+func (rec *Recorder) Fixture() Fixture {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	fixture := rec.fixture
+	fixture.Interactions = append([]Interaction(nil), rec.fixture.Interactions...)
+	return fixture
+}
+
+// Save writes everything recorded so far to fileName
+func (rec *Recorder) Save(fileName string) error {
+	data, err := json.MarshalIndent(rec.Fixture(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	// fixtures are only readable by their owner in case scrubbing missed a secret
+	if err := os.WriteFile(fileName, data, 0600); err != nil {
+		return fmt.Errorf("error writing fixture: %w", err)
+	}
+	return nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a recorded fixture
+// instead of sending them. Interactions are replayed in the order they were recorded,
+// each at most once, so repeated requests get the responses of their recorded repetitions
+type Replayer struct {
+	mu      sync.Mutex
+	fixture *Fixture
+	used    []bool
+}
+
+// NewReplayer returns a Replayer answering from fixture
+func NewReplayer(fixture *Fixture) *Replayer {
+	return &Replayer{fixture: fixture, used: make([]bool, len(fixture.Interactions))}
+}
+
+// LoadReplayer returns a Replayer answering from the fixture in fileName
+func LoadReplayer(fileName string) (*Replayer, error) {
+	fixture, err := LoadFixture(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(fixture), nil
+}
+
+// RoundTrip answers the request with the first unused recorded interaction matching it
+func (rep *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, _, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	for i, interaction := range rep.fixture.Interactions {
+		if rep.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		rep.used[i] = true
+
+		resp := &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}
+		if resp.Header == nil {
+			resp.Header = http.Header{}
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("%s %s: %w", recorded.Method, recorded.Path, ErrNoInteraction)
+}
+
+// Unused returns the recorded interactions that have not been replayed, to check
+// that the code under test made every request it was expected to make
+func (rep *Replayer) Unused() []Interaction {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range rep.fixture.Interactions {
+		if !rep.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// matches reports whether a recorded request is the same as a new one
+func matches(recorded RecordedRequest, req RecordedRequest) bool {
+	if recorded.Method != req.Method || recorded.Path != req.Path {
+		return false
+	}
+	if len(recorded.Params) == 0 && len(req.Params) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(recorded.Params, req.Params)
+}
+
+// recordRequest extracts the identifying parts of a request with secrets scrubbed.
+// It returns the body it consumed so the caller can restore it
+func recordRequest(req *http.Request) (RecordedRequest, []byte, error) {
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Params: url.Values{},
+	}
+	for key, values := range req.URL.Query() {
+		recorded.Params[key] = append(recorded.Params[key], values...)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return recorded, nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body.Close()
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return recorded, nil, fmt.Errorf("failed to parse form: %w", err)
+		}
+		for key, values := range form {
+			recorded.Params[key] = append(recorded.Params[key], values...)
+		}
+	case "multipart/form-data":
+		form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(32 << 20)
+		if err != nil {
+			return recorded, nil, fmt.Errorf("failed to parse multipart form: %w", err)
+		}
+		for key, values := range form.Value {
+			recorded.Params[key] = append(recorded.Params[key], values...)
+		}
+		// files are identified by name and content hash to keep fixtures small
+		for key, headers := range form.File {
+			for _, header := range headers {
+				file, err := header.Open()
+				if err != nil {
+					return recorded, nil, fmt.Errorf("failed to read multipart file: %w", err)
+				}
+				sum := sha256.New()
+				io.Copy(sum, file)
+				file.Close()
+				recorded.Params[key] = append(recorded.Params[key], header.Filename+":"+hex.EncodeToString(sum.Sum(nil)))
+			}
+		}
+		form.RemoveAll()
+	}
+
+	for key, values := range recorded.Params {
+		for i, value := range values {
+			if secretKeys[key] {
+				values[i] = scrubbed
+			} else if key == "json" {
+				values[i] = scrubBody([]byte(value))
+			}
+		}
+	}
+	if len(recorded.Params) == 0 {
+		recorded.Params = nil
+	}
+	return recorded, body, nil
+}
+
+// scrubCookie replaces the value of a session cookie in a Set-Cookie header
+func scrubCookie(header string) string {
+	name, rest, found := strings.Cut(header, "=")
+	if !found {
+		return header
+	}
+	_, attributes, _ := strings.Cut(rest, ";")
+	if attributes != "" {
+		attributes = ";" + attributes
+	}
+	return name + "=" + scrubbed + attributes
+}
+
+// scrubBody replaces password fields of a JSON object, leaving other bodies untouched
+func scrubBody(body []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return string(body)
+	}
+
+	changed := false
+	for key := range fields {
+		if secretKeys[key] {
+			fields[key] = json.RawMessage(`"` + scrubbed + `"`)
+			changed = true
+		}
+	}
+	if !changed {
+		return string(body)
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
@@ -0,0 +1,301 @@
+package qbttest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// rssFolder is an RSS folder of the fake
+type rssFolder struct {
+	folders map[string]*rssFolder
+	feeds   map[string]*rssFeed
+}
+
+// rssFeed is an RSS feed of the fake. It never fetches anything, articles are added by tests
+type rssFeed struct {
+	uid      string
+	url      string
+	title    string
+	articles []qbt.RSSArticle
+}
+
+func newRSSFolder() *rssFolder {
+	return &rssFolder{folders: map[string]*rssFolder{}, feeds: map[string]*rssFeed{}}
+}
+
+// AddRSSArticles adds articles to the feed at path, as if it had been refreshed.
+// It returns false if there is no feed at path
+func (s *Server) AddRSSArticles(path string, articles ...qbt.RSSArticle) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parent, name, ok := s.rssParent(path)
+	if !ok || parent.feeds[name] == nil {
+		return false
+	}
+	feed := parent.feeds[name]
+	feed.articles = append(feed.articles, articles...)
+	return true
+}
+
+// rssParent returns the folder holding the item at path and the name of the item in it
+func (s *Server) rssParent(path string) (*rssFolder, string, bool) {
+	names := strings.Split(path, qbt.RSSPathSeparator)
+	folder := s.rss
+	for _, name := range names[:len(names)-1] {
+		folder = folder.folders[name]
+		if folder == nil {
+			return nil, "", false
+		}
+	}
+	name := names[len(names)-1]
+	return folder, name, name != ""
+}
+
+// exists reports whether the folder has an item with the name
+func (f *rssFolder) exists(name string) bool {
+	return f.folders[name] != nil || f.feeds[name] != nil
+}
+
+// hasURL reports whether a feed in the folder or its subfolders has the url
+func (f *rssFolder) hasURL(url string) bool {
+	for _, feed := range f.feeds {
+		if feed.url == url {
+			return true
+		}
+	}
+	for _, folder := range f.folders {
+		if folder.hasURL(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// feedByURL returns the name and feed with the url in the folder or its subfolders
+func (f *rssFolder) feedByURL(url string) (string, *rssFeed) {
+	for name, feed := range f.feeds {
+		if feed.url == url {
+			return name, feed
+		}
+	}
+	for _, folder := range f.folders {
+		if name, feed := folder.feedByURL(url); feed != nil {
+			return name, feed
+		}
+	}
+	return "", nil
+}
+
+// allFeeds returns the feeds in the folder and its subfolders
+func (f *rssFolder) allFeeds() []*rssFeed {
+	var feeds []*rssFeed
+	for _, feed := range f.feeds {
+		feeds = append(feeds, feed)
+	}
+	for _, folder := range f.folders {
+		feeds = append(feeds, folder.allFeeds()...)
+	}
+	return feeds
+}
+
+// tree returns the folder in the form rss/items reports it
+func (f *rssFolder) tree(withData bool) map[string]interface{} {
+	items := map[string]interface{}{}
+	for name, folder := range f.folders {
+		items[name] = folder.tree(withData)
+	}
+	for name, feed := range f.feeds {
+		item := map[string]interface{}{"uid": feed.uid, "url": feed.url}
+		if withData {
+			articles := feed.articles
+			if articles == nil {
+				articles = []qbt.RSSArticle{}
+			}
+			item["title"] = feed.title
+			item["lastBuildDate"] = ""
+			item["isLoading"] = false
+			item["hasError"] = false
+			item["articles"] = articles
+		}
+		items[name] = item
+	}
+	return items
+}
+
+// newUID returns a random feed uid in the braced form qbittorrent uses
+func newUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("{%x-%x-%x-%x-%x}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (s *Server) handleRSSAddFolder(w http.ResponseWriter, r *http.Request) {
+	parent, name, ok := s.rssParent(r.Form.Get("path"))
+	if !ok || parent.exists(name) {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	parent.folders[name] = newRSSFolder()
+}
+
+func (s *Server) handleRSSAddFeed(w http.ResponseWriter, r *http.Request) {
+	url := r.Form.Get("url")
+	path := r.Form.Get("path")
+	if path == "" {
+		path = url
+	}
+	parent, name, ok := s.rssParent(path)
+	if url == "" || !ok || parent.exists(name) || s.rss.hasURL(url) {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	parent.feeds[name] = &rssFeed{uid: newUID(), url: url, title: name}
+}
+
+func (s *Server) handleRSSRemoveItem(w http.ResponseWriter, r *http.Request) {
+	parent, name, ok := s.rssParent(r.Form.Get("path"))
+	if !ok || !parent.exists(name) {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	delete(parent.folders, name)
+	delete(parent.feeds, name)
+}
+
+func (s *Server) handleRSSMoveItem(w http.ResponseWriter, r *http.Request) {
+	from, fromName, ok := s.rssParent(r.Form.Get("itemPath"))
+	if !ok || !from.exists(fromName) {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	to, toName, ok := s.rssParent(r.Form.Get("destPath"))
+	if !ok || to.exists(toName) {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+
+	if folder := from.folders[fromName]; folder != nil {
+		delete(from.folders, fromName)
+		to.folders[toName] = folder
+	} else {
+		feed := from.feeds[fromName]
+		delete(from.feeds, fromName)
+		to.feeds[toName] = feed
+	}
+}
+
+func (s *Server) handleRSSItems(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.rss.tree(r.Form.Get("withData") == "true"))
+}
+
+func (s *Server) handleRSSMarkAsRead(w http.ResponseWriter, r *http.Request) {
+	feeds, ok := s.rssFeeds(r.Form.Get("itemPath"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	articleID := r.Form.Get("articleId")
+	for _, feed := range feeds {
+		for i := range feed.articles {
+			if articleID == "" || feed.articles[i].ID == articleID {
+				feed.articles[i].IsRead = true
+			}
+		}
+	}
+}
+
+func (s *Server) handleRSSRefreshItem(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.rssFeeds(r.Form.Get("itemPath")); !ok {
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleRSSSetFeedURL(w http.ResponseWriter, r *http.Request) {
+	url := r.Form.Get("url")
+	parent, name, ok := s.rssParent(r.Form.Get("path"))
+	if !ok || parent.feeds[name] == nil || url == "" || s.rss.hasURL(url) {
+		http.Error(w, "Conflict", http.StatusConflict)
+		return
+	}
+	parent.feeds[name].url = url
+}
+
+// rssFeeds returns the feed at path or the feeds in the folder at path, the root folder if empty
+func (s *Server) rssFeeds(path string) ([]*rssFeed, bool) {
+	if path == "" {
+		return s.rss.allFeeds(), true
+	}
+	parent, name, ok := s.rssParent(path)
+	if !ok {
+		return nil, false
+	}
+	if folder := parent.folders[name]; folder != nil {
+		return folder.allFeeds(), true
+	}
+	if feed := parent.feeds[name]; feed != nil {
+		return []*rssFeed{feed}, true
+	}
+	return nil, false
+}
+
+func (s *Server) handleRSSSetRule(w http.ResponseWriter, r *http.Request) {
+	var rule qbt.RSSRule
+	name := r.Form.Get("ruleName")
+	if name == "" || json.Unmarshal([]byte(r.Form.Get("ruleDef")), &rule) != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	s.rssRules[name] = rule
+}
+
+func (s *Server) handleRSSRenameRule(w http.ResponseWriter, r *http.Request) {
+	// qbittorrent silently ignores unknown rules and taken names
+	name, newName := r.Form.Get("ruleName"), r.Form.Get("newRuleName")
+	rule, ok := s.rssRules[name]
+	if _, taken := s.rssRules[newName]; !ok || taken {
+		return
+	}
+	delete(s.rssRules, name)
+	s.rssRules[newName] = rule
+}
+
+func (s *Server) handleRSSRemoveRule(w http.ResponseWriter, r *http.Request) {
+	delete(s.rssRules, r.Form.Get("ruleName"))
+}
+
+func (s *Server) handleRSSRules(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.rssRules)
+}
+
+func (s *Server) handleRSSMatchingArticles(w http.ResponseWriter, r *http.Request) {
+	var prefs qbt.Preferences
+	data, _ := json.Marshal(s.preferences)
+	json.Unmarshal(data, &prefs)
+	opts := qbt.NewRSSMatchOptions(prefs)
+
+	rule := s.rssRules[r.Form.Get("ruleName")]
+	matching := map[string][]string{}
+	for _, url := range rule.AffectedFeeds {
+		name, feed := s.rss.feedByURL(url)
+		if feed == nil {
+			continue
+		}
+		for _, article := range feed.articles {
+			matched, err := rule.Matches(article, opts)
+			if err != nil {
+				// qbittorrent matches nothing with an invalid regex
+				break
+			}
+			if matched {
+				matching[name] = append(matching[name], article.Title)
+			}
+		}
+	}
+	writeJSON(w, matching)
+}
@@ -0,0 +1,182 @@
+package qbttest
+
+import (
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// searchJob is a search of the fake. Searches finish as soon as they are started
+type searchJob struct {
+	status  string
+	results []qbt.SearchResult
+}
+
+// AddSearchResults adds results the search plugins of the fake find. A search finds
+// the results with all the words of its pattern in their file name
+func (s *Server) AddSearchResults(results ...qbt.SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.searchIndex = append(s.searchIndex, results...)
+}
+
+// searchJobParam returns the job with the id in the request, or writes a 404
+func (s *Server) searchJobParam(w http.ResponseWriter, r *http.Request) (int, *searchJob, bool) {
+	id, err := strconv.Atoi(r.Form.Get("id"))
+	job := s.searchJobs[id]
+	if err != nil || job == nil {
+		http.NotFound(w, r)
+		return 0, nil, false
+	}
+	return id, job, true
+}
+
+func (s *Server) handleSearchStart(w http.ResponseWriter, r *http.Request) {
+	pattern := r.Form.Get("pattern")
+	if pattern == "" || r.Form.Get("plugins") == "" || r.Form.Get("category") == "" {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	job := &searchJob{status: qbt.SearchStopped, results: []qbt.SearchResult{}}
+	words := strings.Fields(strings.ToLower(pattern))
+	for _, result := range s.searchIndex {
+		name := strings.ToLower(result.FileName)
+		found := true
+		for _, word := range words {
+			found = found && strings.Contains(name, word)
+		}
+		if found {
+			job.results = append(job.results, result)
+		}
+	}
+
+	s.nextSearchID++
+	s.searchJobs[s.nextSearchID] = job
+	writeJSON(w, qbt.SearchJob{ID: s.nextSearchID})
+}
+
+func (s *Server) handleSearchStop(w http.ResponseWriter, r *http.Request) {
+	if _, job, ok := s.searchJobParam(w, r); ok {
+		job.status = qbt.SearchStopped
+	}
+}
+
+func (s *Server) handleSearchStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Form.Get("id") != "" {
+		id, job, ok := s.searchJobParam(w, r)
+		if ok {
+			writeJSON(w, []qbt.SearchJob{{ID: id, Status: job.status, Total: len(job.results)}})
+		}
+		return
+	}
+
+	jobs := []qbt.SearchJob{}
+	for id, job := range s.searchJobs {
+		jobs = append(jobs, qbt.SearchJob{ID: id, Status: job.status, Total: len(job.results)})
+	}
+	writeJSON(w, jobs)
+}
+
+func (s *Server) handleSearchResults(w http.ResponseWriter, r *http.Request) {
+	_, job, ok := s.searchJobParam(w, r)
+	if !ok {
+		return
+	}
+
+	total := len(job.results)
+	offset, _ := strconv.Atoi(r.Form.Get("offset"))
+	limit, _ := strconv.Atoi(r.Form.Get("limit"))
+	if offset < 0 {
+		offset += total
+	}
+	if offset < 0 || offset > total {
+		http.Error(w, "Offset is out of range", http.StatusConflict)
+		return
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	writeJSON(w, qbt.SearchResults{
+		Results: job.results[offset:end],
+		Status:  job.status,
+		Total:   total,
+	})
+}
+
+func (s *Server) handleSearchDelete(w http.ResponseWriter, r *http.Request) {
+	if id, _, ok := s.searchJobParam(w, r); ok {
+		delete(s.searchJobs, id)
+	}
+}
+
+// searchPluginCategories are the categories the search plugins of the fake support
+var searchPluginCategories = []qbt.SearchCategory{
+	{ID: "all", Name: "All categories"},
+	{ID: "movies", Name: "Movies"},
+	{ID: "tv", Name: "TV shows"},
+	{ID: "software", Name: "Software"},
+}
+
+// AddSearchPlugin installs a search plugin, as if it had been installed before the test
+func (s *Server) AddSearchPlugin(plugin qbt.SearchPlugin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.searchPlugins[plugin.Name] = plugin
+}
+
+func (s *Server) handleSearchPlugins(w http.ResponseWriter, r *http.Request) {
+	plugins := []qbt.SearchPlugin{}
+	for _, plugin := range s.searchPlugins {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	writeJSON(w, plugins)
+}
+
+func (s *Server) handleSearchInstallPlugin(w http.ResponseWriter, r *http.Request) {
+	// qbittorrent downloads and installs plugins in the background, the fake right away.
+	// Plugins are named after their file like in qbittorrent
+	for _, source := range strings.Split(r.Form.Get("sources"), "|") {
+		if !strings.HasSuffix(source, ".py") {
+			continue
+		}
+		name := strings.TrimSuffix(path.Base(source), ".py")
+		s.searchPlugins[name] = qbt.SearchPlugin{
+			Name:                name,
+			FullName:            name,
+			Version:             "1.0",
+			URL:                 source,
+			Enabled:             true,
+			SupportedCategories: searchPluginCategories,
+		}
+	}
+}
+
+func (s *Server) handleSearchUninstallPlugin(w http.ResponseWriter, r *http.Request) {
+	for _, name := range strings.Split(r.Form.Get("names"), "|") {
+		delete(s.searchPlugins, name)
+	}
+}
+
+func (s *Server) handleSearchEnablePlugin(w http.ResponseWriter, r *http.Request) {
+	enable := r.Form.Get("enable") == "true"
+	for _, name := range strings.Split(r.Form.Get("names"), "|") {
+		if plugin, ok := s.searchPlugins[name]; ok {
+			plugin.Enabled = enable
+			s.searchPlugins[name] = plugin
+		}
+	}
+}
+
+func (s *Server) handleSearchUpdatePlugins(w http.ResponseWriter, r *http.Request) {
+	// the plugins of the fake are always up to date
+}
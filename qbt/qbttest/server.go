@@ -0,0 +1,226 @@
+// Package qbttest provides an in-memory fake of the qbittorrent Web API for tests.
+//
+// The fake implements the api/v2 endpoints used by the qbt client with realistic
+// state transitions, so code built on the client can be tested without a real
+// qbittorrent instance:
+//
+//	srv := qbttest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	err := client.DownloadLinks([]string{"magnet:?xt=urn:btih:..."}, qbt.DownloadOptions{})
+//
+// To pin behaviour against a specific qbittorrent release, record the traffic of a
+// live instance with a Recorder and serve it back with a Replayer:
+//
+//	rec := qbttest.NewRecorder(nil)
+//	client := qbt.NewClient("http://localhost:8080", qbt.WithTransport(rec))
+//	...
+//	err := rec.Save("testdata/qbittorrent-5.0.5.json")
+//
+//	rep, err := qbttest.LoadReplayer("testdata/qbittorrent-5.0.5.json")
+//	client := qbt.NewClient("http://localhost:8080", qbt.WithTransport(rep))
+package qbttest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+const (
+	// DefaultUsername is the username the fake accepts unless configured otherwise
+	DefaultUsername = "admin"
+	// DefaultPassword is the password the fake accepts unless configured otherwise
+	DefaultPassword = "adminadmin"
+	// DefaultWebAPIVersion is the Web API version the fake reports unless configured otherwise
+	DefaultWebAPIVersion = "2.11.2"
+
+	apiBase   = "/api/v2/"
+	cookieSID = "SID"
+)
+
+// Server is a fake qbittorrent instance served over HTTP
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	username      string
+	password      string
+	webAPIVersion qbt.APIVersion
+	sessions      map[string]bool
+
+	torrents    map[string]*torrent
+	categories  map[string]qbt.Category
+	tags        map[string]bool
+	preferences map[string]json.RawMessage
+	transfer    transfer
+	rss         *rssFolder
+	rssRules    map[string]qbt.RSSRule
+
+	searchIndex   []qbt.SearchResult
+	searchJobs    map[int]*searchJob
+	nextSearchID  int
+	searchPlugins map[string]qbt.SearchPlugin
+
+	// sync/maindata and sync/torrentPeers count their rids separately, so each
+	// snapshot map only ever holds the last maxSnapshots rids
+	rid           int
+	snapshots     map[int]snapshot
+	peersRid      int
+	peerSnapshots map[int]peerSnapshot
+}
+
+// transfer holds the global transfer state
+type transfer struct {
+	dlLimit  int
+	ulLimit  int
+	altSpeed bool
+}
+
+// ServerOption configures a Server created by NewServer
+type ServerOption func(*Server)
+
+// WithServerCredentials sets the username and password the fake accepts
+func WithServerCredentials(username string, password string) ServerOption {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithWebAPIVersion makes the fake behave like a qbittorrent with the given Web API version,
+// e.g. "2.8.3" to only provide torrents/pause and torrents/resume instead of stop and start
+func WithWebAPIVersion(version string) ServerOption {
+	return func(s *Server) {
+		parsed, err := qbt.ParseAPIVersion(version)
+		if err != nil {
+			panic("qbttest: " + err.Error())
+		}
+		s.webAPIVersion = parsed
+	}
+}
+
+// NewServer starts a fake qbittorrent. Call Close when done
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		username:      DefaultUsername,
+		password:      DefaultPassword,
+		sessions:      map[string]bool{},
+		torrents:      map[string]*torrent{},
+		categories:    map[string]qbt.Category{},
+		tags:          map[string]bool{},
+		preferences:   defaultPreferences(),
+		snapshots:     map[int]snapshot{},
+		peerSnapshots: map[int]peerSnapshot{},
+		rss:           newRSSFolder(),
+		rssRules:      map[string]qbt.RSSRule{},
+		searchJobs:    map[int]*searchJob{},
+		searchPlugins: map[string]qbt.SearchPlugin{},
+	}
+	s.webAPIVersion, _ = qbt.ParseAPIVersion(DefaultWebAPIVersion)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a client for the fake that is already logged in
+func (s *Server) Client(opts ...qbt.Option) *qbt.Client {
+	client := qbt.NewClient(s.URL, opts...)
+	if err := client.Login(s.username, s.password); err != nil {
+		panic("qbttest: login failed: " + err.Error())
+	}
+	return client
+}
+
+// ExpireSessions invalidates all session cookies, like qbittorrent does after the session timeout
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = map[string]bool{}
+}
+
+// route is the handler of a single endpoint
+type route struct {
+	method  string
+	handler func(s *Server, w http.ResponseWriter, r *http.Request)
+	since   qbt.APIVersion // first Web API version providing the endpoint
+	until   qbt.APIVersion // first Web API version without the endpoint, zero if it still exists
+}
+
+// available reports whether the endpoint exists in the given Web API version
+func (rt route) available(version qbt.APIVersion) bool {
+	if !version.AtLeast(rt.since) {
+		return false
+	}
+	return rt.until == (qbt.APIVersion{}) || !version.AtLeast(rt.until)
+}
+
+// serveHTTP authenticates and dispatches a request to its endpoint
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, apiBase)
+	rt, ok := routes[endpoint]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !ok || !strings.HasPrefix(r.URL.Path, apiBase) || !rt.available(s.webAPIVersion) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != rt.method {
+		w.Header().Set("Allow", rt.method)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if endpoint != "auth/login" && !s.authenticated(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// qbittorrent reads parameters from the query string and the body alike
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.ParseMultipartForm(32 << 20)
+	} else {
+		r.ParseForm()
+	}
+
+	rt.handler(s, w, r)
+}
+
+// authenticated reports whether the request carries a valid session cookie
+func (s *Server) authenticated(r *http.Request) bool {
+	cookie, err := r.Cookie(cookieSID)
+	return err == nil && s.sessions[cookie.Value]
+}
+
+// newSession creates a session and returns its id
+func (s *Server) newSession() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	sid := hex.EncodeToString(buf)
+	s.sessions[sid] = true
+	return sid
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeText writes a plain text response body
+func writeText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.Write([]byte(text))
+}
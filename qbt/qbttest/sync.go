@@ -0,0 +1,305 @@
+package qbttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// maxSnapshots bounds how many past states are kept to answer incremental sync requests.
+// Clients asking for an older rid get a full update, like they would from qbittorrent
+const maxSnapshots = 64
+
+// snapshot is the state of the fake at a given rid, in the form sync/maindata reports it
+type snapshot struct {
+	torrents    map[string]map[string]interface{}
+	categories  map[string]qbt.Category
+	tags        []string
+	serverState map[string]interface{}
+}
+
+// takeSnapshot captures the current state of the fake
+func (s *Server) takeSnapshot() snapshot {
+	snap := snapshot{
+		torrents:    map[string]map[string]interface{}{},
+		categories:  map[string]qbt.Category{},
+		tags:        s.tagList(),
+		serverState: toFields(s.serverState()),
+	}
+	for hash, t := range s.torrents {
+		fields := toFields(t.info)
+		// sync/maindata identifies torrents by the map key instead of a hash field
+		delete(fields, "hash")
+		snap.torrents[hash] = fields
+	}
+	for name, category := range s.categories {
+		snap.categories[name] = category
+	}
+	return snap
+}
+
+// serverState returns the global transfer state of the fake
+func (s *Server) serverState() map[string]interface{} {
+	var dlSpeed, upSpeed, downloaded, uploaded int64
+	for _, t := range s.torrents {
+		dlSpeed += t.info.Dlspeed
+		upSpeed += t.info.Upspeed
+		downloaded += t.info.DownloadedSession
+		uploaded += t.info.UploadedSession
+	}
+
+	var queueing bool
+	json.Unmarshal(s.preferences["queueing_enabled"], &queueing)
+
+	return map[string]interface{}{
+		"connection_status":    "connected",
+		"dht_nodes":            350,
+		"dl_info_data":         downloaded,
+		"dl_info_speed":        dlSpeed,
+		"dl_rate_limit":        s.transfer.dlLimit,
+		"up_info_data":         uploaded,
+		"up_info_speed":        upSpeed,
+		"up_rate_limit":        s.transfer.ulLimit,
+		"queueing":             queueing,
+		"use_alt_speed_limits": s.transfer.altSpeed,
+		"refresh_interval":     1500,
+	}
+}
+
+func (s *Server) handleMainData(w http.ResponseWriter, r *http.Request) {
+	rid, _ := strconv.Atoi(r.Form.Get("rid"))
+	current := s.takeSnapshot()
+
+	s.rid++
+	s.snapshots[s.rid] = current
+	delete(s.snapshots, s.rid-maxSnapshots)
+
+	previous, ok := s.snapshots[rid]
+	if rid == 0 || !ok {
+		writeJSON(w, map[string]interface{}{
+			"rid":          s.rid,
+			"full_update":  true,
+			"torrents":     current.torrents,
+			"categories":   current.categories,
+			"tags":         current.tags,
+			"server_state": current.serverState,
+		})
+		return
+	}
+
+	response := map[string]interface{}{"rid": s.rid}
+
+	torrents := map[string]interface{}{}
+	var torrentsRemoved []string
+	for hash, fields := range current.torrents {
+		if changed := changedFields(previous.torrents[hash], fields); len(changed) > 0 {
+			torrents[hash] = changed
+		}
+	}
+	for hash := range previous.torrents {
+		if _, ok := current.torrents[hash]; !ok {
+			torrentsRemoved = append(torrentsRemoved, hash)
+		}
+	}
+	if len(torrents) > 0 {
+		response["torrents"] = torrents
+	}
+	if len(torrentsRemoved) > 0 {
+		response["torrents_removed"] = torrentsRemoved
+	}
+
+	categories := map[string]qbt.Category{}
+	var categoriesRemoved []string
+	for name, category := range current.categories {
+		if old, ok := previous.categories[name]; !ok || old != category {
+			categories[name] = category
+		}
+	}
+	for name := range previous.categories {
+		if _, ok := current.categories[name]; !ok {
+			categoriesRemoved = append(categoriesRemoved, name)
+		}
+	}
+	if len(categories) > 0 {
+		response["categories"] = categories
+	}
+	if len(categoriesRemoved) > 0 {
+		response["categories_removed"] = categoriesRemoved
+	}
+
+	if added := missing(current.tags, previous.tags); len(added) > 0 {
+		response["tags"] = added
+	}
+	if removed := missing(previous.tags, current.tags); len(removed) > 0 {
+		response["tags_removed"] = removed
+	}
+
+	if changed := changedFields(previous.serverState, current.serverState); len(changed) > 0 {
+		response["server_state"] = changed
+	}
+
+	writeJSON(w, response)
+}
+
+// peerSnapshot is the peer set of a torrent at a given rid, in the form sync/torrentPeers reports it
+type peerSnapshot struct {
+	hash  string
+	peers map[string]map[string]interface{}
+}
+
+func (s *Server) handleTorrentPeers(w http.ResponseWriter, r *http.Request) {
+	hash := strings.ToLower(r.Form.Get("hash"))
+	t, ok := s.torrents[hash]
+	if !ok {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	rid, _ := strconv.Atoi(r.Form.Get("rid"))
+	current := peerSnapshot{hash: hash, peers: map[string]map[string]interface{}{}}
+	for addr, peer := range t.peers {
+		current.peers[addr] = toFields(peer)
+	}
+
+	s.peersRid++
+	s.peerSnapshots[s.peersRid] = current
+	delete(s.peerSnapshots, s.peersRid-maxSnapshots)
+
+	previous, ok := s.peerSnapshots[rid]
+	if rid == 0 || !ok || previous.hash != hash {
+		writeJSON(w, map[string]interface{}{
+			"rid":         s.peersRid,
+			"full_update": true,
+			"show_flags":  true,
+			"peers":       current.peers,
+		})
+		return
+	}
+
+	response := map[string]interface{}{"rid": s.peersRid, "show_flags": true}
+
+	peers := map[string]interface{}{}
+	var peersRemoved []string
+	for addr, fields := range current.peers {
+		if changed := changedFields(previous.peers[addr], fields); len(changed) > 0 {
+			peers[addr] = changed
+		}
+	}
+	for addr := range previous.peers {
+		if _, ok := current.peers[addr]; !ok {
+			peersRemoved = append(peersRemoved, addr)
+		}
+	}
+	if len(peers) > 0 {
+		response["peers"] = peers
+	}
+	if len(peersRemoved) > 0 {
+		response["peers_removed"] = peersRemoved
+	}
+
+	writeJSON(w, response)
+}
+
+// changedFields returns the fields of current that differ from previous
+func changedFields(previous map[string]interface{}, current map[string]interface{}) map[string]interface{} {
+	changed := map[string]interface{}{}
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+// missing returns the items of list that are not in other
+func missing(list []string, other []string) []string {
+	var result []string
+	for _, item := range list {
+		found := false
+		for _, o := range other {
+			if o == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// toFields converts v into the generic form it has when decoded from JSON
+func toFields(v interface{}) map[string]interface{} {
+	data, _ := json.Marshal(v)
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+// defaultPreferences are the preferences of a freshly installed qbittorrent
+func defaultPreferences() map[string]json.RawMessage {
+	prefs := qbt.Preferences{
+		Locale:                             "en",
+		TorrentContentLayout:               "Original",
+		TorrentStopCondition:               "None",
+		SavePath:                           "/downloads/",
+		TempPath:                           "/downloads/temp/",
+		ScanDirs:                           map[string]interface{}{},
+		QueueingEnabled:                    true,
+		MaxActiveDls:                       3,
+		MaxActiveTorrents:                  5,
+		MaxActiveUls:                       3,
+		SlowTorrentDlRateThreshold:         2,
+		SlowTorrentUlRateThreshold:         2,
+		SlowTorrentInactiveTimer:           60,
+		MaxRatio:                           -1,
+		ListenPort:                         6881,
+		UPNP:                               true,
+		MaxConnections:                     500,
+		MaxConnectionsPerTorrent:           100,
+		MaxUls:                             20,
+		MaxUlsPerTorrent:                   4,
+		UTPEnabled:                         true,
+		LimitUTPRate:                       true,
+		LimitLANPeers:                      true,
+		AltDlLimit:                         10240,
+		AltUlLimit:                         10240,
+		ScheduleFromHour:                   8,
+		ScheduleToHour:                     20,
+		DHTEnabled:                         true,
+		PexEnabled:                         true,
+		LSDEnabled:                         true,
+		ProxyType:                          "None",
+		ProxyPort:                          8080,
+		WebUIDomainList:                    "*",
+		WebUIAddress:                       "*",
+		WebUIPort:                          8080,
+		WebUIUsername:                      DefaultUsername,
+		WebUICSRFProtectionEnabled:         true,
+		WebUIClickjackingProtectionEnabled: true,
+		WebUISecureCookieEnabled:           true,
+		WebUIHostHeaderValidationEnabled:   true,
+		WebUIMaxAuthFailCount:              5,
+		WebUIBanDuration:                   3600,
+		WebUISessionTimeout:                3600,
+		BypassAuthSubnetWhitelist:          "",
+		DynDNSDomain:                       "changeme.dyndns.org",
+		RSSRefreshInterval:                 30,
+		RSSMaxArtPerFeed:                   50,
+		RSSFetchDelay:                      2,
+		RSSSmartEpisodeFilters:             strings.Join(qbt.DefaultSmartEpisodeFilters, "\n"),
+		ResumeDataStorageType:              "Legacy",
+		TorrentContentRemoveOption:         "Delete",
+		RefreshInterval:                    1500,
+	}
+
+	fields := map[string]json.RawMessage{}
+	data, _ := json.Marshal(prefs)
+	json.Unmarshal(data, &fields)
+	return fields
+}
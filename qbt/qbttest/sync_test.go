@@ -0,0 +1,36 @@
+package qbttest
+
+import (
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+func TestSnapshotsAreBounded(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddTorrent(qbt.TorrentInfo{Hash: "0123456789abcdef0123456789abcdef01234567", Name: "test"})
+	client := srv.Client()
+
+	// interleave both sync endpoints unevenly so they cannot share a rid counter
+	for i := 0; i < 3*maxSnapshots; i++ {
+		for j := 0; j < 2; j++ {
+			if _, err := client.MainData("0"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := client.TorrentPeers("0123456789abcdef0123456789abcdef01234567", "0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if n := len(srv.snapshots); n > maxSnapshots {
+		t.Errorf("kept %d maindata snapshots, want at most %d", n, maxSnapshots)
+	}
+	if n := len(srv.peerSnapshots); n > maxSnapshots {
+		t.Errorf("kept %d peer snapshots, want at most %d", n, maxSnapshots)
+	}
+}
@@ -0,0 +1,227 @@
+package qbttest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// torrent is the state the fake keeps for a single torrent
+type torrent struct {
+	info     qbt.TorrentInfo
+	tags     []string
+	trackers []qbt.Tracker
+	peers    map[string]qbt.TorrentPeer // by ip:port
+}
+
+// setTags replaces the tags of the torrent, keeping the info in sync
+func (t *torrent) setTags(tags []string) {
+	sort.Strings(tags)
+	t.tags = tags
+	t.info.Tags = strings.Join(tags, ", ")
+}
+
+// hasTag reports whether the torrent has a tag
+func (t *torrent) hasTag(tag string) bool {
+	for _, existing := range t.tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTorrent adds a torrent to the fake as if it had been added through the WebUI.
+// Hash is required; an empty State is set to downloading or uploading depending on Progress
+func (s *Server) AddTorrent(info qbt.TorrentInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info.Hash = strings.ToLower(info.Hash)
+	if info.State == "" {
+		info.State = s.runningState(info.Progress)
+	}
+	if info.AddedOn == 0 {
+		info.AddedOn = time.Now().Unix()
+	}
+	if info.Category != "" {
+		if _, ok := s.categories[info.Category]; !ok {
+			s.categories[info.Category] = qbt.Category{Name: info.Category}
+		}
+	}
+
+	t := &torrent{info: info}
+	t.setTags(splitList(info.Tags, ","))
+	for _, tag := range t.tags {
+		s.tags[tag] = true
+	}
+	s.torrents[info.Hash] = t
+}
+
+// Torrent returns the current state of a torrent in the fake
+func (s *Server) Torrent(hash string) (qbt.TorrentInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.torrents[strings.ToLower(hash)]
+	if !ok {
+		return qbt.TorrentInfo{}, false
+	}
+	return t.info, true
+}
+
+// UpdateTorrent changes a torrent in the fake, e.g. to simulate download progress.
+// It returns false if there is no torrent with the hash
+func (s *Server) UpdateTorrent(hash string, update func(info *qbt.TorrentInfo)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.torrents[strings.ToLower(hash)]
+	if !ok {
+		return false
+	}
+	update(&t.info)
+	return true
+}
+
+// CompleteTorrent finishes downloading a torrent, moving it to the seeding state
+func (s *Server) CompleteTorrent(hash string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.torrents[strings.ToLower(hash)]
+	if !ok {
+		return false
+	}
+	t.info.Progress = 1
+	t.info.AmountLeft = 0
+	t.info.Completed = t.info.Size
+	t.info.Downloaded = t.info.Size
+	t.info.Dlspeed = 0
+	t.info.CompletionOn = time.Now().Unix()
+	if s.isStopped(t.info.State) {
+		t.info.State = s.stoppedState(1)
+	} else {
+		t.info.State = s.runningState(1)
+	}
+	return true
+}
+
+// SetPeers replaces the peers connected to a torrent, keyed by ip:port.
+// It returns false if there is no torrent with the hash
+func (s *Server) SetPeers(hash string, peers map[string]qbt.TorrentPeer) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.torrents[strings.ToLower(hash)]
+	if !ok {
+		return false
+	}
+	t.peers = map[string]qbt.TorrentPeer{}
+	for addr, peer := range peers {
+		t.peers[addr] = peer
+	}
+	return true
+}
+
+// runningState is the state of a torrent that is not stopped
+func (s *Server) runningState(progress float64) string {
+	if progress >= 1 {
+		return "uploading"
+	}
+	return "downloading"
+}
+
+// stoppedState is the state of a stopped torrent, which qbittorrent 5 renamed from paused
+func (s *Server) stoppedState(progress float64) string {
+	prefix := "stopped"
+	if !s.webAPIVersion.AtLeast(qbt.APIVersion{Major: 2, Minor: 11}) {
+		prefix = "paused"
+	}
+	if progress >= 1 {
+		return prefix + "UP"
+	}
+	return prefix + "DL"
+}
+
+// isStopped reports whether state is a stopped (or paused) state
+func (s *Server) isStopped(state string) bool {
+	return strings.HasPrefix(state, "stopped") || strings.HasPrefix(state, "paused")
+}
+
+// selectTorrents returns the torrents matching a "|" separated list of hashes or "all"
+func (s *Server) selectTorrents(hashes string) []*torrent {
+	var selected []*torrent
+	if hashes == "all" {
+		for _, t := range s.torrents {
+			selected = append(selected, t)
+		}
+		return selected
+	}
+	for _, hash := range splitList(hashes, "|") {
+		if t, ok := s.torrents[strings.ToLower(hash)]; ok {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
+
+// torrentFromLink builds a torrent from a magnet link, returning false for unsupported links
+func torrentFromLink(link string) (*torrent, bool) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, false
+	}
+
+	switch u.Scheme {
+	case "magnet":
+		query := u.Query()
+		hash := strings.TrimPrefix(strings.ToLower(query.Get("xt")), "urn:btih:")
+		if hash == "" || hash == strings.ToLower(query.Get("xt")) {
+			return nil, false
+		}
+		name := query.Get("dn")
+		if name == "" {
+			name = hash
+		}
+		t := &torrent{info: qbt.TorrentInfo{Hash: hash, Name: name, MagnetURI: link}}
+		for tier, tracker := range query["tr"] {
+			t.trackers = append(t.trackers, qbt.Tracker{URL: tracker, Tier: tier, Status: 1})
+		}
+		return t, true
+	case "http", "https":
+		// the fake cannot download the file, so derive a stable hash from the url
+		sum := sha1.Sum([]byte(link))
+		return &torrent{info: qbt.TorrentInfo{Hash: hex.EncodeToString(sum[:]), Name: path.Base(u.Path)}}, true
+	default:
+		return nil, false
+	}
+}
+
+// torrentFromFile builds a torrent from an uploaded .torrent file.
+// The fake does not parse bencode, so the hash is derived from the file contents
+func torrentFromFile(fileName string, contents []byte) (*torrent, bool) {
+	if len(contents) == 0 || contents[0] != 'd' {
+		return nil, false
+	}
+	sum := sha1.Sum(contents)
+	name := strings.TrimSuffix(path.Base(fileName), ".torrent")
+	return &torrent{info: qbt.TorrentInfo{Hash: hex.EncodeToString(sum[:]), Name: name, Size: int64(len(contents))}}, true
+}
+
+// splitList splits a delimited parameter, dropping empty and surrounding whitespace
+func splitList(list string, delimiter string) []string {
+	var items []string
+	for _, item := range strings.Split(list, delimiter) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
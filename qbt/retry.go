@@ -0,0 +1,130 @@
+package qbt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// nonIdempotent endpoints change state relative to the current state, so repeating
+// them after the server may have processed them could add or toggle things twice
+var nonIdempotent = map[string]bool{
+	apiBase + "torrents/add":                      true,
+	apiBase + "torrents/increasePrio":             true,
+	apiBase + "torrents/decreasePrio":             true,
+	apiBase + "torrents/toggleSequentialDownload": true,
+	apiBase + "torrents/toggleFirstLastPiecePrio": true,
+	apiBase + "transfer/toggleSpeedLimitsMode":    true,
+	apiBase + "rss/addFeed":                       true,
+	apiBase + "rss/addFolder":                     true,
+	apiBase + "search/start":                      true,
+	apiBase + "search/installPlugin":              true,
+	apiBase + "search/updatePlugins":              true,
+}
+
+// defaultRetryableStatus are the statuses qbittorrent (or a proxy in front of it)
+// returns while it is restarting
+var defaultRetryableStatus = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how requests that fail with transient errors are retried.
+// Requests to endpoints that are not idempotent, such as torrents/add, are only
+// retried when the connection could not be established at all
+type RetryPolicy struct {
+	MaxAttempts     int           // total attempts including the first one, values below 2 disable retries
+	MinBackoff      time.Duration // delay before the first retry, doubled for every further attempt
+	MaxBackoff      time.Duration // upper bound of the delay between attempts
+	Jitter          bool          // randomize each delay between half and all of its value
+	RetryableStatus []int         // statuses worth retrying, defaults to 500, 502, 503 and 504
+
+	// Retryable overrides the default classification of errors when set
+	Retryable func(endpoint string, err error) bool
+}
+
+// DefaultRetryPolicy returns a policy suited to riding out a qbittorrent restart
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  15 * time.Second,
+		Jitter:      true,
+	}
+}
+
+// sendWithRetry performs a request, retrying it according to the client's RetryPolicy
+func (c *Client) sendWithRetry(ctx context.Context, endpoint string, build func() (*http.Request, error)) (*http.Response, error) {
+	policy := c.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(endpoint, build)
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(endpoint, err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("gave up retrying after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry reports whether a failed attempt is worth repeating
+func (p *RetryPolicy) shouldRetry(endpoint string, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(endpoint, err)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// the request never reached qbittorrent, so repeating it is always safe
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if nonIdempotent[endpoint] {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		statuses := p.RetryableStatus
+		if statuses == nil {
+			statuses = defaultRetryableStatus
+		}
+		for _, status := range statuses {
+			if apiErr.StatusCode == status {
+				return true
+			}
+		}
+		return false
+	}
+
+	// any other transport error, such as a reset connection or a timeout
+	return true
+}
+
+// backoff returns the delay before the attempt following the given one
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter && delay > 1 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
@@ -0,0 +1,99 @@
+package qbt_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// restarting answers the first requests to some endpoints with 503 Service Unavailable,
+// like qbittorrent behind a proxy while it restarts, and counts the requests it gets
+type restarting struct {
+	mu       sync.Mutex
+	failures map[string]int // remaining failures by endpoint
+	requests map[string]int
+}
+
+func (s *restarting) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/api/v2/")
+	s.mu.Lock()
+	s.requests[endpoint]++
+	fail := s.failures[endpoint] > 0
+	if fail {
+		s.failures[endpoint]--
+	}
+	s.mu.Unlock()
+
+	switch {
+	case fail:
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	case endpoint == "app/webapiVersion":
+		// endpoints newer than the 2.0 API check the version first
+		io.WriteString(w, "2.11.2")
+	case endpoint == "torrents/info":
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[]")
+	default:
+		io.WriteString(w, "Ok.")
+	}
+}
+
+// count returns the number of requests sent to endpoint
+func (s *restarting) count(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// newRestartingClient returns a client retrying up to three attempts against a server
+// whose endpoints in failures fail with 503 the given number of times
+func newRestartingClient(t *testing.T, failures map[string]int) (*qbt.Client, *restarting) {
+	server := &restarting{failures: failures, requests: map[string]int{}}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	client := qbt.NewClient(srv.URL)
+	client.RetryPolicy = &qbt.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	return client, server
+}
+
+func TestRetryServiceUnavailable(t *testing.T) {
+	client, server := newRestartingClient(t, map[string]int{"torrents/info": 2})
+	if _, err := client.Torrents(qbt.TorrentsOptions{}); err != nil {
+		t.Fatalf("request failing twice with 503: %v", err)
+	}
+	if n := server.count("torrents/info"); n != 3 {
+		t.Errorf("sent torrents/info %d times, want 3", n)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	client, server := newRestartingClient(t, map[string]int{"torrents/info": 5})
+	_, err := client.Torrents(qbt.TorrentsOptions{})
+	var apiErr *qbt.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want a 503 APIError", err)
+	}
+	if n := server.count("torrents/info"); n != 3 {
+		t.Errorf("sent torrents/info %d times, want 3", n)
+	}
+}
+
+func TestNoRetryOfTorrentsAdd(t *testing.T) {
+	client, server := newRestartingClient(t, map[string]int{"torrents/add": 1})
+	err := client.DownloadLinks([]string{"magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567"}, qbt.DownloadOptions{})
+	var apiErr *qbt.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want a 503 APIError", err)
+	}
+	if n := server.count("torrents/add"); n != 1 {
+		t.Errorf("sent torrents/add %d times, want 1", n)
+	}
+}
@@ -0,0 +1,210 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// RSSPathSeparator separates the folders of an RSS item path, e.g. `Linux\Debian`
+const RSSPathSeparator = `\`
+
+// UnmarshalJSON decodes the item tree returned by rss/items, where folders and
+// feeds are both objects and feeds are recognized by their uid
+func (f *RSSFolder) UnmarshalJSON(data []byte) error {
+	var items map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	f.Folders = map[string]RSSFolder{}
+	f.Feeds = map[string]RSSFeed{}
+	for name, item := range items {
+		var probe struct {
+			UID interface{} `json:"uid"`
+		}
+		if err := json.Unmarshal(item, &probe); err != nil {
+			return fmt.Errorf("rss item %s: %w", name, err)
+		}
+
+		if _, isFeed := probe.UID.(string); isFeed {
+			var feed RSSFeed
+			if err := json.Unmarshal(item, &feed); err != nil {
+				return fmt.Errorf("rss feed %s: %w", name, err)
+			}
+			f.Feeds[name] = feed
+			continue
+		}
+
+		var folder RSSFolder
+		if err := json.Unmarshal(item, &folder); err != nil {
+			return err
+		}
+		f.Folders[name] = folder
+	}
+	return nil
+}
+
+// AllFeeds returns the feeds in the folder and its subfolders by their path relative to it
+func (f RSSFolder) AllFeeds() map[string]RSSFeed {
+	feeds := map[string]RSSFeed{}
+	for name, feed := range f.Feeds {
+		feeds[name] = feed
+	}
+	for folderName, folder := range f.Folders {
+		for name, feed := range folder.AllFeeds() {
+			feeds[folderName+RSSPathSeparator+name] = feed
+		}
+	}
+	return feeds
+}
+
+// RSS Endpoints
+
+// AddRSSFolder creates an RSS folder, path being e.g. `Linux\Debian` for a subfolder
+func (c *Client) AddRSSFolder(path string) error {
+	return c.AddRSSFolderCtx(context.Background(), path)
+}
+
+// AddRSSFolderCtx is AddRSSFolder with a context that controls the lifetime of the request
+func (c *Client) AddRSSFolderCtx(ctx context.Context, path string) error {
+	resp, err := c.post(ctx, apiBase+"rss/addFolder", map[string]string{"path": path})
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusConflict: "failed to add folder",
+		})
+	}
+	return resp.Body.Close()
+}
+
+// AddRSSFeed subscribes to the feed at url, path being the full path of the new item
+// or empty to add it to the root folder under its url
+func (c *Client) AddRSSFeed(url string, path string) error {
+	return c.AddRSSFeedCtx(context.Background(), url, path)
+}
+
+// AddRSSFeedCtx is AddRSSFeed with a context that controls the lifetime of the request
+func (c *Client) AddRSSFeedCtx(ctx context.Context, url string, path string) error {
+	opts := map[string]string{"url": url}
+	if path != "" {
+		opts["path"] = path
+	}
+	resp, err := c.post(ctx, apiBase+"rss/addFeed", opts)
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusConflict: "failed to add feed",
+		})
+	}
+	return resp.Body.Close()
+}
+
+// RemoveRSSItem removes a folder, with everything in it, or a feed
+func (c *Client) RemoveRSSItem(path string) error {
+	return c.RemoveRSSItemCtx(context.Background(), path)
+}
+
+// RemoveRSSItemCtx is RemoveRSSItem with a context that controls the lifetime of the request
+func (c *Client) RemoveRSSItemCtx(ctx context.Context, path string) error {
+	resp, err := c.post(ctx, apiBase+"rss/removeItem", map[string]string{"path": path})
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusConflict: "failed to remove item",
+		})
+	}
+	return resp.Body.Close()
+}
+
+// MoveRSSItem moves or renames a folder or feed
+func (c *Client) MoveRSSItem(itemPath string, destPath string) error {
+	return c.MoveRSSItemCtx(context.Background(), itemPath, destPath)
+}
+
+// MoveRSSItemCtx is MoveRSSItem with a context that controls the lifetime of the request
+func (c *Client) MoveRSSItemCtx(ctx context.Context, itemPath string, destPath string) error {
+	opts := map[string]string{
+		"itemPath": itemPath,
+		"destPath": destPath,
+	}
+	resp, err := c.post(ctx, apiBase+"rss/moveItem", opts)
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusConflict: "failed to move item",
+		})
+	}
+	return resp.Body.Close()
+}
+
+// RSSItems returns the tree of RSS folders and feeds, with the feed titles,
+// states and articles if withData is set
+func (c *Client) RSSItems(withData bool) (root RSSFolder, err error) {
+	return c.RSSItemsCtx(context.Background(), withData)
+}
+
+// RSSItemsCtx is RSSItems with a context that controls the lifetime of the request
+func (c *Client) RSSItemsCtx(ctx context.Context, withData bool) (root RSSFolder, err error) {
+	opts := map[string]string{"withData": strconv.FormatBool(withData)}
+	resp, err := c.get(ctx, apiBase+"rss/items", opts)
+	if err != nil {
+		return root, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+		return root, err
+	}
+	return root, nil
+}
+
+// MarkRSSAsRead marks an article as read, or all articles of the item if articleID is empty
+func (c *Client) MarkRSSAsRead(itemPath string, articleID string) error {
+	return c.MarkRSSAsReadCtx(context.Background(), itemPath, articleID)
+}
+
+// MarkRSSAsReadCtx is MarkRSSAsRead with a context that controls the lifetime of the request
+func (c *Client) MarkRSSAsReadCtx(ctx context.Context, itemPath string, articleID string) error {
+	opts := map[string]string{"itemPath": itemPath}
+	if articleID != "" {
+		opts["articleId"] = articleID
+	}
+	resp, err := c.post(ctx, apiBase+"rss/markAsRead", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// RefreshRSSItem fetches a feed, or all feeds in a folder, right away
+func (c *Client) RefreshRSSItem(itemPath string) error {
+	return c.RefreshRSSItemCtx(context.Background(), itemPath)
+}
+
+// RefreshRSSItemCtx is RefreshRSSItem with a context that controls the lifetime of the request
+func (c *Client) RefreshRSSItemCtx(ctx context.Context, itemPath string) error {
+	resp, err := c.post(ctx, apiBase+"rss/refreshItem", map[string]string{"itemPath": itemPath})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SetRSSFeedURL changes the url of a feed
+func (c *Client) SetRSSFeedURL(path string, url string) error {
+	return c.SetRSSFeedURLCtx(context.Background(), path, url)
+}
+
+// SetRSSFeedURLCtx is SetRSSFeedURL with a context that controls the lifetime of the request
+func (c *Client) SetRSSFeedURLCtx(ctx context.Context, path string, url string) error {
+	opts := map[string]string{
+		"path": path,
+		"url":  url,
+	}
+	resp, err := c.post(ctx, apiBase+"rss/setFeedURL", opts)
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusConflict: "failed to set feed url",
+		})
+	}
+	return resp.Body.Close()
+}
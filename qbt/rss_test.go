@@ -0,0 +1,131 @@
+package qbt_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// feedURLs returns the urls of the feeds below root by their path
+func feedURLs(t *testing.T, client *qbt.Client) map[string]string {
+	t.Helper()
+	root, err := client.RSSItems(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	urls := map[string]string{}
+	for path, feed := range root.AllFeeds() {
+		urls[path] = feed.URL
+	}
+	return urls
+}
+
+func TestRSSFeeds(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if err := client.AddRSSFolder("Linux"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddRSSFeed("https://example.com/debian.rss", `Linux\Debian`); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddRSSFeed("https://example.com/news.rss", ""); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		`Linux\Debian`:                 "https://example.com/debian.rss",
+		"https://example.com/news.rss": "https://example.com/news.rss",
+	}
+	if got := feedURLs(t, client); !reflect.DeepEqual(got, want) {
+		t.Errorf("feeds are %v, want %v", got, want)
+	}
+
+	// qbittorrent refuses a second subscription to the same url
+	err := client.AddRSSFeed("https://example.com/debian.rss", "Debian")
+	if !errors.Is(err, qbt.ErrConflict) {
+		t.Errorf("got %v for a duplicate feed, want ErrConflict", err)
+	}
+
+	if err := client.MoveRSSItem("https://example.com/news.rss", "News"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetRSSFeedURL(`Linux\Debian`, "https://example.com/debian-security.rss"); err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]string{
+		`Linux\Debian`: "https://example.com/debian-security.rss",
+		"News":         "https://example.com/news.rss",
+	}
+	if got := feedURLs(t, client); !reflect.DeepEqual(got, want) {
+		t.Errorf("feeds are %v after moving and changing the url, want %v", got, want)
+	}
+
+	if err := client.RemoveRSSItem("Linux"); err != nil {
+		t.Fatal(err)
+	}
+	if got := feedURLs(t, client); len(got) != 1 || got["News"] == "" {
+		t.Errorf("feeds are %v after removing the folder, want News only", got)
+	}
+}
+
+func TestRSSArticles(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if err := client.AddRSSFeed("https://example.com/debian.rss", "Debian"); err != nil {
+		t.Fatal(err)
+	}
+	srv.AddRSSArticles("Debian",
+		qbt.RSSArticle{ID: "1", Title: "Debian 12.7 released"},
+		qbt.RSSArticle{ID: "2", Title: "Debian 12.8 released"},
+	)
+	if err := client.MarkRSSAsRead("Debian", "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := client.RSSItems(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unread []string
+	for _, article := range root.Feeds["Debian"].Articles {
+		if !article.IsRead {
+			unread = append(unread, article.Title)
+		}
+	}
+	sort.Strings(unread)
+	if want := []string{"Debian 12.8 released"}; !reflect.DeepEqual(unread, want) {
+		t.Errorf("unread articles are %q, want %q", unread, want)
+	}
+
+	if err := client.RefreshRSSItem("Debian"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RefreshRSSItem("Ubuntu"); !errors.Is(err, qbt.ErrNotFound) {
+		t.Errorf("got %v refreshing a missing feed, want ErrNotFound", err)
+	}
+}
+
+func TestNoRetryOfRSSAddFeed(t *testing.T) {
+	client, server := newRestartingClient(t, map[string]int{"rss/addFeed": 1, "rss/addFolder": 1})
+
+	// a feed or folder added before the server failed would be added twice
+	var apiErr *qbt.APIError
+	if err := client.AddRSSFolder("Linux"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v adding a folder, want a 503 APIError", err)
+	}
+	if err := client.AddRSSFeed("https://example.com/debian.rss", ""); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v adding a feed, want a 503 APIError", err)
+	}
+	if n := server.count("rss/addFolder") + server.count("rss/addFeed"); n != 2 {
+		t.Errorf("sent %d requests, want 1 for each", n)
+	}
+}
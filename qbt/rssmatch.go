@@ -0,0 +1,439 @@
+package qbt
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSmartEpisodeFilters are the smart episode filters of a new qbittorrent installation
+var DefaultSmartEpisodeFilters = []string{
+	`s(\d+)e(\d+)`,                    // s01e01
+	`(\d+)x(\d+)`,                     // 01x01
+	`(\d{4}[.\-]\d{1,2}[.\-]\d{1,2})`, // 2017.01.01
+	`(\d{1,2}[.\-]\d{1,2}[.\-]\d{4})`, // 01.01.2017
+}
+
+// rssDateLayouts are the layouts of the dates of articles and of the last match of rules
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+}
+
+var episodeFilterRegex = regexp.MustCompile(`^(\d{1,4})x(.*;)$`)
+
+// Patterns used by episode filters to find the season and episode in a title
+var (
+	seasonEpisodeRegex = regexp.MustCompile(`(?i)\bs0?(\d{1,4})[ -_\.]?e(0?\d{1,4})(?:\D|\b)`)
+	seasonXRegex       = regexp.MustCompile(`(?i)\b(\d{1,4})x(0?\d{1,4})(?:\D|\b)`)
+)
+
+// RSSMatchOptions are the preferences rule evaluation depends on
+type RSSMatchOptions struct {
+	// SmartEpisodeFilters recognize episodes for the smart filter, DefaultSmartEpisodeFilters if empty
+	SmartEpisodeFilters []string
+	// DownloadRepackProperEpisodes lets the smart filter download REPACK and PROPER
+	// releases of episodes that were downloaded already
+	DownloadRepackProperEpisodes bool
+}
+
+// NewRSSMatchOptions returns the options set by the preferences of an instance
+func NewRSSMatchOptions(prefs Preferences) RSSMatchOptions {
+	var filters []string
+	for _, filter := range strings.Split(prefs.RSSSmartEpisodeFilters, "\n") {
+		if filter = strings.TrimSpace(filter); filter != "" {
+			filters = append(filters, filter)
+		}
+	}
+	return RSSMatchOptions{
+		SmartEpisodeFilters:          filters,
+		DownloadRepackProperEpisodes: prefs.RSSDownloadRepackProperEpisodes,
+	}
+}
+
+// Matches reports whether the rule matches an article the way rss/matchingArticles
+// evaluates it, so episodes the smart filter has seen are those of PreviouslyMatchedEpisodes only
+func (r RSSRule) Matches(article RSSArticle, opts RSSMatchOptions) (bool, error) {
+	m, err := r.matcher(opts)
+	if err != nil {
+		return false, err
+	}
+	matched, _ := m.matches(article)
+	return matched, nil
+}
+
+// MatchArticles returns the articles the rule would download, in order, whether or not
+// it is enabled. Like qbittorrent when it downloads, an episode the smart filter lets
+// through is remembered for the following articles, and a match starts the IgnoreDays period
+func (r RSSRule) MatchArticles(articles []RSSArticle, opts RSSMatchOptions) ([]RSSArticle, error) {
+	m, err := r.matcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []RSSArticle
+	for _, article := range articles {
+		if m.match(article) {
+			matched = append(matched, article)
+		}
+	}
+	return matched, nil
+}
+
+// MatchFeeds returns the articles the rule would download from the feeds it affects,
+// keyed like feeds, e.g. by the paths returned by RSSFolder.AllFeeds. Feeds are
+// evaluated in the order of their keys and share the state of the smart filter
+func (r RSSRule) MatchFeeds(feeds map[string]RSSFeed, opts RSSMatchOptions) (map[string][]RSSArticle, error) {
+	m, err := r.matcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	affected := map[string]bool{}
+	for _, url := range r.AffectedFeeds {
+		affected[url] = true
+	}
+	var keys []string
+	for key, feed := range feeds {
+		if affected[feed.URL] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	matched := map[string][]RSSArticle{}
+	for _, key := range keys {
+		for _, article := range feeds[key].Articles {
+			if m.match(article) {
+				matched[key] = append(matched[key], article)
+			}
+		}
+	}
+	return matched, nil
+}
+
+// rssMatcher evaluates a rule with its expressions compiled
+type rssMatcher struct {
+	rule RSSRule
+	// mustContain and mustNotContain hold alternatives, each matching if all its patterns do
+	mustContain    [][]*regexp.Regexp
+	mustNotContain [][]*regexp.Regexp
+	smartFilter    *regexp.Regexp
+	repacks        bool
+	lastMatch      time.Time
+	episodes       map[string]bool // previously matched episodes
+}
+
+// matcher compiles the rule
+func (r RSSRule) matcher(opts RSSMatchOptions) (*rssMatcher, error) {
+	m := &rssMatcher{rule: r, repacks: opts.DownloadRepackProperEpisodes, episodes: map[string]bool{}}
+
+	var err error
+	if m.mustContain, err = r.compileExpression(r.MustContain); err != nil {
+		return nil, fmt.Errorf("mustContain: %w", err)
+	}
+	if m.mustNotContain, err = r.compileExpression(r.MustNotContain); err != nil {
+		return nil, fmt.Errorf("mustNotContain: %w", err)
+	}
+
+	if r.SmartFilter {
+		filters := opts.SmartEpisodeFilters
+		if len(filters) == 0 {
+			filters = DefaultSmartEpisodeFilters
+		}
+		pattern := `(?i)(?:_|\b)(?:` + strings.Join(filters, `)|(?:`) + `)(?:_|\b)`
+		if m.smartFilter, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("smart episode filters: %w", err)
+		}
+	}
+
+	m.lastMatch, _ = parseRSSDate(r.LastMatch)
+	for _, episode := range r.PreviouslyMatchedEpisodes {
+		m.episodes[episode] = true
+	}
+	return m, nil
+}
+
+// compileExpression compiles a mustContain or mustNotContain expression, nil if it is empty
+func (r RSSRule) compileExpression(expression string) ([][]*regexp.Regexp, error) {
+	if expression == "" {
+		return nil, nil
+	}
+
+	if r.UseRegex {
+		re, err := regexp.Compile("(?i)" + expression)
+		if err != nil {
+			return nil, err
+		}
+		return [][]*regexp.Regexp{{re}}, nil
+	}
+
+	var alternatives [][]*regexp.Regexp
+	for _, alternative := range strings.Split(expression, "|") {
+		// an empty alternative matches everything, like "expr|" does as a regex
+		patterns := []*regexp.Regexp{}
+		for _, wildcard := range strings.Fields(alternative) {
+			re, err := regexp.Compile("(?i)" + wildcardToRegex(wildcard))
+			if err != nil {
+				return nil, err
+			}
+			patterns = append(patterns, re)
+		}
+		alternatives = append(alternatives, patterns)
+	}
+	return alternatives, nil
+}
+
+// match evaluates the rule against an article and on a match updates the state of
+// the rule the way qbittorrent does when it downloads the article. The last match is
+// the date of the article rather than the time of the evaluation, so the result does
+// not depend on when articles are evaluated
+func (m *rssMatcher) match(article RSSArticle) bool {
+	matched, episodes := m.matches(article)
+	if !matched {
+		return false
+	}
+	for _, episode := range episodes {
+		m.episodes[episode] = true
+	}
+	if date, err := parseRSSDate(article.Date); err == nil {
+		m.lastMatch = date
+	}
+	return true
+}
+
+// matches evaluates the rule against an article, returning the episodes the smart
+// filter would remember if the article were downloaded
+func (m *rssMatcher) matches(article RSSArticle) (bool, []string) {
+	if m.rule.IgnoreDays > 0 && !m.lastMatch.IsZero() {
+		// an article without a valid date is older than any match, like in qbittorrent
+		date, _ := parseRSSDate(article.Date)
+		if date.Before(m.lastMatch.AddDate(0, 0, m.rule.IgnoreDays)) {
+			return false, nil
+		}
+	}
+
+	title := article.Title
+	if m.mustContain != nil && !anyAlternative(m.mustContain, title) {
+		return false, nil
+	}
+	if m.mustNotContain != nil && anyAlternative(m.mustNotContain, title) {
+		return false, nil
+	}
+	if !m.matchesEpisodeFilter(title) {
+		return false, nil
+	}
+	return m.matchesSmartFilter(title)
+}
+
+// matchesEpisodeFilter reports whether the title is one of the episodes of the episode filter
+func (m *rssMatcher) matchesEpisodeFilter(title string) bool {
+	if m.rule.EpisodeFilter == "" {
+		return true
+	}
+	filter := episodeFilterRegex.FindStringSubmatch(m.rule.EpisodeFilter)
+	if filter == nil {
+		return false
+	}
+	season := filter[1]
+	seasonOurs := atoi(season)
+
+	for _, episode := range strings.Split(filter[2], ";") {
+		if episode == "" {
+			continue
+		}
+		// trim leading zeros, but keep a lone zero for episode zero
+		for len(episode) > 1 && episode[0] == '0' {
+			episode = episode[1:]
+		}
+
+		if !strings.Contains(episode, "-") {
+			pattern := fmt.Sprintf(`(?i)\b(?:s0?%[1]s[ -_\.]?e0?%[2]s|%[1]sx0?%[2]s)(?:\D|\b)`,
+				regexp.QuoteMeta(season), regexp.QuoteMeta(episode))
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(title) {
+				return true
+			}
+			continue
+		}
+
+		theirs := seasonEpisodeRegex.FindStringSubmatch(title)
+		if theirs == nil {
+			theirs = seasonXRegex.FindStringSubmatch(title)
+		}
+		if theirs == nil {
+			continue
+		}
+		seasonTheirs, episodeTheirs := atoi(theirs[1]), atoi(theirs[2])
+
+		if strings.HasSuffix(episode, "-") {
+			// open range
+			episodeOurs := atoi(strings.TrimSuffix(episode, "-"))
+			if (seasonTheirs == seasonOurs && episodeTheirs >= episodeOurs) || seasonTheirs > seasonOurs {
+				return true
+			}
+			continue
+		}
+
+		bounds := strings.Split(episode, "-")
+		first, last := atoi(bounds[0]), atoi(bounds[len(bounds)-1])
+		if first > last {
+			continue
+		}
+		if seasonTheirs == seasonOurs && first <= episodeTheirs && episodeTheirs <= last {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSmartFilter reports whether the episode of the title wasn't downloaded yet,
+// returning the episode names to remember if it is
+func (m *rssMatcher) matchesSmartFilter(title string) (bool, []string) {
+	if m.smartFilter == nil {
+		return true, nil
+	}
+	episode := smartEpisodeName(m.smartFilter, title)
+	if episode == "" {
+		return true, nil
+	}
+	if !m.episodes[episode] {
+		return true, []string{episode}
+	}
+
+	if !m.repacks {
+		return false, nil
+	}
+	upper := strings.ToUpper(title)
+	isRepack := strings.Contains(upper, "REPACK")
+	isProper := strings.Contains(upper, "PROPER")
+	if !isRepack && !isProper {
+		return false, nil
+	}
+
+	full := episode
+	if isRepack {
+		full += "-REPACK"
+	}
+	if isProper {
+		full += "-PROPER"
+	}
+	if m.episodes[full] {
+		return false, nil
+	}
+	episodes := []string{full}
+	if isRepack && isProper {
+		// the separate releases are not wanted after the combined one
+		episodes = append(episodes, episode+"-REPACK", episode+"-PROPER")
+	}
+	return true, append(episodes, episode)
+}
+
+// smartEpisodeName returns the name the smart filter gives the episode of the title,
+// its captured numbers joined by "x", e.g. "1x2" for "Show.S01E02"
+func smartEpisodeName(filter *regexp.Regexp, title string) string {
+	match := filter.FindStringSubmatch(title)
+	if match == nil {
+		return ""
+	}
+	var parts []string
+	for _, capture := range match[1:] {
+		if capture == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(capture); err == nil {
+			capture = strconv.Itoa(n)
+		}
+		parts = append(parts, capture)
+	}
+	return strings.Join(parts, "x")
+}
+
+// anyAlternative reports whether all the patterns of one of the alternatives match s
+func anyAlternative(alternatives [][]*regexp.Regexp, s string) bool {
+	for _, patterns := range alternatives {
+		if allMatch(patterns, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// allMatch reports whether all the patterns match s
+func allMatch(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if !re.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// wildcardToRegex converts a wildcard into an unanchored regular expression the way Qt
+// does: "*" matches any characters but "/", "?" any one of them and "[...]" or "[!...]"
+// a set of characters
+func wildcardToRegex(wildcard string) string {
+	var b strings.Builder
+	for i := 0; i < len(wildcard); i++ {
+		switch c := wildcard[i]; c {
+		case '*':
+			b.WriteString(`[^/]*`)
+		case '?':
+			b.WriteString(`[^/]`)
+		case '[':
+			start := i + 1
+			if start < len(wildcard) && wildcard[start] == '!' {
+				start++
+			}
+			search := start
+			if search < len(wildcard) && wildcard[search] == ']' {
+				// a leading "]" belongs to the set
+				search++
+			}
+			end := strings.IndexByte(wildcard[search:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			end += search
+
+			b.WriteByte('[')
+			if start > i+1 {
+				b.WriteByte('^')
+			}
+			for _, r := range wildcard[start:end] {
+				if r == '\\' || r == '[' || r == ']' || r == '^' {
+					b.WriteByte('\\')
+				}
+				b.WriteRune(r)
+			}
+			b.WriteByte(']')
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(wildcard[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// parseRSSDate parses the date of an article or the last match of a rule
+func parseRSSDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range rssDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// atoi converts s like Qt does, returning 0 if it isn't a number
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
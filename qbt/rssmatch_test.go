@@ -0,0 +1,165 @@
+package qbt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// titles returns the titles of articles
+func titles(articles []qbt.RSSArticle) []string {
+	var titles []string
+	for _, article := range articles {
+		titles = append(titles, article.Title)
+	}
+	return titles
+}
+
+func TestRSSRuleIgnoreDays(t *testing.T) {
+	articles := []qbt.RSSArticle{
+		{Title: "Show 1", Date: "Mon, 01 Jan 2024 12:00:00 +0000"},
+		{Title: "Show 2", Date: "Tue, 02 Jan 2024 12:00:00 +0000"},
+		{Title: "Show 3", Date: "Wed, 03 Jan 2024 12:00:00 +0000"},
+		{Title: "Show 4", Date: "Fri, 05 Jan 2024 12:00:00 +0000"},
+	}
+
+	tests := []struct {
+		name      string
+		lastMatch string
+		want      []string
+	}{
+		{"without a last match", "", []string{"Show 1", "Show 3", "Show 4"}},
+		{"within the period of the last match", "Sun, 31 Dec 2023 06:00:00 +0000", []string{"Show 2", "Show 4"}},
+		{"after the period of the last match", "Thu, 01 Dec 2023 12:00:00 +0000", []string{"Show 1", "Show 3", "Show 4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := qbt.RSSRule{Enabled: true, MustContain: "show", IgnoreDays: 2, LastMatch: tt.lastMatch}
+			matched, err := rule.MatchArticles(articles, qbt.RSSMatchOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(matched); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matched %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRSSRuleMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  qbt.RSSRule
+		title string
+		want  bool
+	}{
+		{"wildcard", qbt.RSSRule{MustContain: "ubuntu*desktop"}, "Ubuntu 24.04 Desktop amd64", true},
+		{"wildcard mismatch", qbt.RSSRule{MustContain: "ubuntu*desktop"}, "Ubuntu 24.04 Server amd64", false},
+		{"all words", qbt.RSSRule{MustContain: "linux iso"}, "Linux Mint 22 ISO", true},
+		{"missing word", qbt.RSSRule{MustContain: "linux iso"}, "Linux Mint 22", false},
+		{"alternatives", qbt.RSSRule{MustContain: "debian|fedora"}, "Fedora 40 Workstation", true},
+		{"must not contain", qbt.RSSRule{MustContain: "fedora", MustNotContain: "beta|rc?"}, "Fedora 41 Beta", false},
+		{"must not contain mismatch", qbt.RSSRule{MustContain: "fedora", MustNotContain: "beta|rc?"}, "Fedora 41", true},
+		{"regex", qbt.RSSRule{UseRegex: true, MustContain: `^show\.s\d+e\d+`}, "Show.S01E02.1080p", true},
+		{"regex mismatch", qbt.RSSRule{UseRegex: true, MustContain: `^show\.s\d+e\d+`}, "Other.Show.S01E02", false},
+		{"episode", qbt.RSSRule{EpisodeFilter: "1x2;8-15;20-;"}, "Show S01E02 720p", true},
+		{"episode range", qbt.RSSRule{EpisodeFilter: "1x2;8-15;20-;"}, "Show 1x10 720p", true},
+		{"episode open range", qbt.RSSRule{EpisodeFilter: "1x2;8-15;20-;"}, "Show S02E01 720p", true},
+		{"episode outside", qbt.RSSRule{EpisodeFilter: "1x2;8-15;20-;"}, "Show S01E05 720p", false},
+		{"episode of previous season", qbt.RSSRule{EpisodeFilter: "2x1-;"}, "Show S01E25 720p", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.Matches(qbt.RSSArticle{Title: tt.title}, qbt.RSSMatchOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("%q matches %q: %v, want %v", tt.rule.MustContain, tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRSSRuleInvalidRegex(t *testing.T) {
+	rule := qbt.RSSRule{UseRegex: true, MustContain: "show("}
+	if _, err := rule.Matches(qbt.RSSArticle{Title: "show"}, qbt.RSSMatchOptions{}); err == nil {
+		t.Error("invalid regex matched without an error")
+	}
+}
+
+func TestRSSRuleSmartFilter(t *testing.T) {
+	articles := []qbt.RSSArticle{
+		{Title: "Show S01E01 720p"},
+		{Title: "Show S01E01 1080p"},
+		{Title: "Show S01E02 720p"},
+		{Title: "Show S01E02 REPACK 720p"},
+		{Title: "Show S01E03 720p"},
+	}
+
+	tests := []struct {
+		name    string
+		rule    qbt.RSSRule
+		repacks bool
+		want    []string
+	}{
+		{
+			"each episode once",
+			qbt.RSSRule{MustContain: "show", SmartFilter: true},
+			false,
+			[]string{"Show S01E01 720p", "Show S01E02 720p", "Show S01E03 720p"},
+		},
+		{
+			"previously matched",
+			qbt.RSSRule{MustContain: "show", SmartFilter: true, PreviouslyMatchedEpisodes: []string{"1x1", "1x3"}},
+			false,
+			[]string{"Show S01E02 720p"},
+		},
+		{
+			"repacks",
+			qbt.RSSRule{MustContain: "show", SmartFilter: true},
+			true,
+			[]string{"Show S01E01 720p", "Show S01E02 720p", "Show S01E02 REPACK 720p", "Show S01E03 720p"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := qbt.RSSMatchOptions{DownloadRepackProperEpisodes: tt.repacks}
+			matched, err := tt.rule.MatchArticles(articles, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(matched); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matched %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRSSMatchingArticles(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	if err := client.AddRSSFeed("https://example.com/shows.xml", "Shows"); err != nil {
+		t.Fatal(err)
+	}
+	srv.AddRSSArticles("Shows",
+		qbt.RSSArticle{ID: "1", Title: "Show S01E01 720p"},
+		qbt.RSSArticle{ID: "2", Title: "Other S01E01 720p"},
+	)
+	rule := qbt.RSSRule{Enabled: true, MustContain: "show", AffectedFeeds: []string{"https://example.com/shows.xml"}}
+	if err := client.SetRSSRule("shows", rule); err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := client.RSSMatchingArticles("shows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string][]string{"Shows": {"Show S01E01 720p"}}; !reflect.DeepEqual(articles, want) {
+		t.Errorf("matching articles are %v, want %v", articles, want)
+	}
+}
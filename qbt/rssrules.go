@@ -0,0 +1,151 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// rssRuleKeys are the JSON keys of the rule fields modeled by RSSRule
+var rssRuleKeys = jsonKeys(reflect.TypeOf(RSSRule{}))
+
+// knownRSSRule has the fields of RSSRule without its JSON methods
+type knownRSSRule RSSRule
+
+// MarshalJSON encodes the rule together with the Extra keys. Missing lists are
+// written as empty ones like qbittorrent does. Empty torrent parameters are left out,
+// since qbittorrent 4.6 and later only read the legacy keys of rules without them
+func (r RSSRule) MarshalJSON() ([]byte, error) {
+	if r.AffectedFeeds == nil {
+		r.AffectedFeeds = []string{}
+	}
+	if r.PreviouslyMatchedEpisodes == nil {
+		r.PreviouslyMatchedEpisodes = []string{}
+	}
+	data, err := marshalWithExtra(knownRSSRule(r), rssRuleKeys, r.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := json.Marshal(r.TorrentParams)
+	if err != nil || string(params) != "{}" {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "torrentParams")
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the rule, keeping unknown keys in Extra
+func (r *RSSRule) UnmarshalJSON(data []byte) error {
+	var known knownRSSRule
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	extra, err := extraFields(data, rssRuleKeys)
+	if err != nil {
+		return err
+	}
+	known.Extra = extra
+	*r = RSSRule(known)
+	return nil
+}
+
+// RSS Rule Endpoints
+
+// SetRSSRule creates the auto-downloading rule with the name or replaces it
+func (c *Client) SetRSSRule(name string, rule RSSRule) error {
+	return c.SetRSSRuleCtx(context.Background(), name, rule)
+}
+
+// SetRSSRuleCtx is SetRSSRule with a context that controls the lifetime of the request
+func (c *Client) SetRSSRuleCtx(ctx context.Context, name string, rule RSSRule) error {
+	ruleDef, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode rule %s: %w", name, err)
+	}
+	opts := map[string]string{
+		"ruleName": name,
+		"ruleDef":  string(ruleDef),
+	}
+	resp, err := c.post(ctx, apiBase+"rss/setRule", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// RenameRSSRule renames an auto-downloading rule
+func (c *Client) RenameRSSRule(name string, newName string) error {
+	return c.RenameRSSRuleCtx(context.Background(), name, newName)
+}
+
+// RenameRSSRuleCtx is RenameRSSRule with a context that controls the lifetime of the request
+func (c *Client) RenameRSSRuleCtx(ctx context.Context, name string, newName string) error {
+	opts := map[string]string{
+		"ruleName":    name,
+		"newRuleName": newName,
+	}
+	resp, err := c.post(ctx, apiBase+"rss/renameRule", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// RemoveRSSRule removes an auto-downloading rule
+func (c *Client) RemoveRSSRule(name string) error {
+	return c.RemoveRSSRuleCtx(context.Background(), name)
+}
+
+// RemoveRSSRuleCtx is RemoveRSSRule with a context that controls the lifetime of the request
+func (c *Client) RemoveRSSRuleCtx(ctx context.Context, name string) error {
+	resp, err := c.post(ctx, apiBase+"rss/removeRule", map[string]string{"ruleName": name})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// RSSRules returns the auto-downloading rules by name
+func (c *Client) RSSRules() (rules map[string]RSSRule, err error) {
+	return c.RSSRulesCtx(context.Background())
+}
+
+// RSSRulesCtx is RSSRules with a context that controls the lifetime of the request
+func (c *Client) RSSRulesCtx(ctx context.Context) (rules map[string]RSSRule, err error) {
+	resp, err := c.get(ctx, apiBase+"rss/rules", nil)
+	if err != nil {
+		return rules, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+// RSSMatchingArticles returns the titles of the articles an auto-downloading rule
+// matches, by the name of their feed
+func (c *Client) RSSMatchingArticles(name string) (articles map[string][]string, err error) {
+	return c.RSSMatchingArticlesCtx(context.Background(), name)
+}
+
+// RSSMatchingArticlesCtx is RSSMatchingArticles with a context that controls the lifetime of the request
+func (c *Client) RSSMatchingArticlesCtx(ctx context.Context, name string) (articles map[string][]string, err error) {
+	resp, err := c.get(ctx, apiBase+"rss/matchingArticles", map[string]string{"ruleName": name})
+	if err != nil {
+		return articles, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&articles); err != nil {
+		return articles, err
+	}
+	return articles, nil
+}
@@ -0,0 +1,46 @@
+package qbt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+func TestRSSRuleTorrentParams(t *testing.T) {
+	tests := []struct {
+		name string
+		rule qbt.RSSRule
+		want bool // whether torrentParams is encoded
+	}{
+		{"legacy keys only", qbt.RSSRule{SavePath: "/downloads/shows", AssignedCategory: "shows"}, false},
+		{"empty tags", qbt.RSSRule{TorrentParams: qbt.RSSTorrentParams{Tags: []string{}}}, false},
+		{"torrent parameters", qbt.RSSRule{TorrentParams: qbt.RSSTorrentParams{Category: "shows"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := fields["torrentParams"]; ok != tt.want {
+				t.Errorf("torrentParams encoded is %v, want %v: %s", ok, tt.want, data)
+			}
+			if _, ok := fields["savePath"]; !ok {
+				t.Errorf("legacy savePath is missing: %s", data)
+			}
+
+			var decoded qbt.RSSRule
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded.SavePath != tt.rule.SavePath || decoded.TorrentParams.Category != tt.rule.TorrentParams.Category {
+				t.Errorf("decoded %+v, want %+v", decoded, tt.rule)
+			}
+		})
+	}
+}
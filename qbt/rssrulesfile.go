@@ -0,0 +1,242 @@
+package qbt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ExportRSSRules writes rules by name in the JSON format of the rule export of qbittorrent,
+// so the file can be imported by qbittorrent as well as by ImportRSSRules
+func ExportRSSRules(w io.Writer, rules map[string]RSSRule) error {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("failed to encode rules: %w", err)
+	}
+
+	// decoding into maps and encoding again sorts the keys at every level like
+	// qbittorrent writes them, numbers are kept as they are
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false) // keep "<" and "&" of expressions readable
+	enc.SetIndent("", "    ")
+	return enc.Encode(doc)
+}
+
+// ImportRSSRules reads rules exported by qbittorrent or ExportRSSRules
+func ImportRSSRules(r io.Reader) (map[string]RSSRule, error) {
+	var rules map[string]RSSRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to decode rules: %w", err)
+	}
+	if rules == nil {
+		rules = map[string]RSSRule{}
+	}
+	return rules, nil
+}
+
+// RSSRuleChangeKind tells what MergeRSSRules did to a rule
+type RSSRuleChangeKind int
+
+// Kinds of rule changes
+const (
+	RSSRuleAdded RSSRuleChangeKind = iota + 1
+	RSSRuleUpdated
+	RSSRuleRemoved
+)
+
+// String returns the name of the kind of change
+func (k RSSRuleChangeKind) String() string {
+	switch k {
+	case RSSRuleAdded:
+		return "added"
+	case RSSRuleUpdated:
+		return "updated"
+	case RSSRuleRemoved:
+		return "removed"
+	default:
+		return fmt.Sprintf("RSSRuleChangeKind(%d)", int(k))
+	}
+}
+
+// RSSRuleChange is a rule added, updated or removed by MergeRSSRules
+type RSSRuleChange struct {
+	Name string
+	Kind RSSRuleChangeKind
+	From *RSSRule // nil if the rule was added
+	To   *RSSRule // nil if the rule was removed
+	// Keys are the JSON keys of the rule that changed when it was updated
+	Keys []string
+}
+
+// String describes the change
+func (c RSSRuleChange) String() string {
+	if c.Kind == RSSRuleUpdated {
+		return c.Name + ": " + c.Kind.String() + " (" + strings.Join(c.Keys, ", ") + ")"
+	}
+	return c.Name + ": " + c.Kind.String()
+}
+
+// RSSRuleChanges lists the changes made by MergeRSSRules, ordered by rule name
+type RSSRuleChanges []RSSRuleChange
+
+// String describes the changes one per line
+func (c RSSRuleChanges) String() string {
+	var b strings.Builder
+	for _, change := range c {
+		b.WriteString(change.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// RSSRuleMergeOptions control what MergeRSSRules changes. Rules missing on the
+// instance are always added
+type RSSRuleMergeOptions struct {
+	// Update replaces rules of the instance that differ
+	Update bool
+	// Prune removes rules of the instance that are not in the merged set
+	Prune bool
+	// DryRun reports the changes without making them
+	DryRun bool
+}
+
+// rssRuleStateKeys record what a rule downloaded on an instance rather than how it is
+// set up, so they are neither compared nor overwritten when merging
+var rssRuleStateKeys = map[string]bool{
+	"lastMatch":                 true,
+	"previouslyMatchedEpisodes": true,
+}
+
+// MergeRSSRules merges rules into the auto-downloading rules of an instance and returns
+// the changes it made. The last match and previously matched episodes of rules the
+// instance has are kept, so updating a rule does not download its episodes again.
+// If a change fails, the changes made before it are returned with the error
+func MergeRSSRules(ctx context.Context, app RSSRuleManager, rules map[string]RSSRule, opts RSSRuleMergeOptions) (RSSRuleChanges, error) {
+	current, err := app.RSSRulesCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	planned, err := planRSSRuleMerge(current, rules, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return planned, nil
+	}
+
+	var changes RSSRuleChanges
+	for _, change := range planned {
+		if change.Kind == RSSRuleRemoved {
+			err = app.RemoveRSSRuleCtx(ctx, change.Name)
+		} else {
+			err = app.SetRSSRuleCtx(ctx, change.Name, *change.To)
+		}
+		if err != nil {
+			return changes, fmt.Errorf("rule %s not %s: %w", change.Name, change.Kind, err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// planRSSRuleMerge returns the changes merging rules into current makes
+func planRSSRuleMerge(current map[string]RSSRule, rules map[string]RSSRule, opts RSSRuleMergeOptions) (RSSRuleChanges, error) {
+	var changes RSSRuleChanges
+	for name, rule := range rules {
+		rule := rule
+		existing, ok := current[name]
+		if !ok {
+			changes = append(changes, RSSRuleChange{Name: name, Kind: RSSRuleAdded, To: &rule})
+			continue
+		}
+		if !opts.Update {
+			continue
+		}
+
+		rule.LastMatch = existing.LastMatch
+		rule.PreviouslyMatchedEpisodes = existing.PreviouslyMatchedEpisodes
+		keys, err := diffRSSRules(existing, rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		if len(keys) > 0 {
+			changes = append(changes, RSSRuleChange{Name: name, Kind: RSSRuleUpdated, From: &existing, To: &rule, Keys: keys})
+		}
+	}
+
+	if opts.Prune {
+		for name, rule := range current {
+			rule := rule
+			if _, ok := rules[name]; !ok {
+				changes = append(changes, RSSRuleChange{Name: name, Kind: RSSRuleRemoved, From: &rule})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes, nil
+}
+
+// diffRSSRules returns the sorted JSON keys of the settings that differ between two rules
+func diffRSSRules(from RSSRule, to RSSRule) ([]string, error) {
+	fromFields, err := rssRuleFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := rssRuleFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for key := range fromFields {
+		keys[key] = true
+	}
+	for key := range toFields {
+		keys[key] = true
+	}
+
+	var changed []string
+	for key := range keys {
+		if rssRuleStateKeys[key] {
+			continue
+		}
+		a, err := normalizeJSON(fromFields[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		b, err := normalizeJSON(toFields[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if !reflect.DeepEqual(a, b) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// rssRuleFields returns the settings of a rule by their JSON key
+func rssRuleFields(rule RSSRule) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,115 @@
+package qbt_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+func TestExportImportRSSRules(t *testing.T) {
+	rules := map[string]qbt.RSSRule{
+		"shows": {
+			Enabled:                   true,
+			MustContain:               "show <720p>",
+			AffectedFeeds:             []string{"https://example.com/shows.xml"},
+			PreviouslyMatchedEpisodes: []string{}, // decoded like qbittorrent writes it
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := qbt.ExportRSSRules(&buf, rules); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"mustContain": "show <720p>"`)) {
+		t.Errorf("export is not readable:\n%s", buf.Bytes())
+	}
+	imported, err := qbt.ImportRSSRules(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(imported, rules) {
+		t.Errorf("imported %+v, want %+v", imported, rules)
+	}
+}
+
+// mergeRules are the rules an instance has before each merge
+var mergeRules = map[string]qbt.RSSRule{
+	"kept":    {Enabled: true, MustContain: "kept"},
+	"changed": {Enabled: true, MustContain: "old", LastMatch: "Mon, 01 Jan 2024 12:00:00 +0000", PreviouslyMatchedEpisodes: []string{"1x1"}},
+	"extra":   {Enabled: true, MustContain: "extra"},
+}
+
+func TestMergeRSSRules(t *testing.T) {
+	merged := map[string]qbt.RSSRule{
+		"kept":    {Enabled: true, MustContain: "kept"},
+		"changed": {Enabled: true, MustContain: "new", IgnoreDays: 1},
+		"added":   {Enabled: true, MustContain: "added"},
+	}
+
+	tests := []struct {
+		name string
+		opts qbt.RSSRuleMergeOptions
+		want []string
+	}{
+		{"add only", qbt.RSSRuleMergeOptions{}, []string{"added: added"}},
+		{"update", qbt.RSSRuleMergeOptions{Update: true}, []string{"added: added", "changed: updated (ignoreDays, mustContain)"}},
+		{"prune", qbt.RSSRuleMergeOptions{Prune: true}, []string{"added: added", "extra: removed"}},
+		{"dry run", qbt.RSSRuleMergeOptions{Update: true, Prune: true, DryRun: true}, []string{"added: added", "changed: updated (ignoreDays, mustContain)", "extra: removed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := qbttest.NewServer()
+			defer srv.Close()
+			client := srv.Client()
+			for name, rule := range mergeRules {
+				if err := client.SetRSSRule(name, rule); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before, err := client.RSSRules()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			changes, err := qbt.MergeRSSRules(context.Background(), client, merged, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes are %q, want %q", got, tt.want)
+			}
+
+			rules, err := client.RSSRules()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.opts.DryRun {
+				if !reflect.DeepEqual(rules, before) {
+					t.Errorf("dry run changed the rules to %+v", rules)
+				}
+				return
+			}
+			if _, ok := rules["added"]; !ok {
+				t.Error("rule added is missing")
+			}
+			if _, ok := rules["extra"]; ok == tt.opts.Prune {
+				t.Errorf("rule extra kept is %v with prune %v", ok, tt.opts.Prune)
+			}
+			if tt.opts.Update {
+				changed := rules["changed"]
+				if changed.MustContain != "new" || changed.LastMatch != mergeRules["changed"].LastMatch ||
+					!reflect.DeepEqual(changed.PreviouslyMatchedEpisodes, []string{"1x1"}) {
+					t.Errorf("updated rule is %+v, want the new settings with the state of the instance", changed)
+				}
+			}
+		})
+	}
+}
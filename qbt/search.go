@@ -0,0 +1,201 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// searchPollInterval is how often RunSearch checks on a search
+	searchPollInterval = time.Second
+	// searchCleanupTimeout bounds deleting the search when RunSearch returns
+	searchCleanupTimeout = 5 * time.Second
+)
+
+// Search Endpoints
+
+// StartSearch starts a search for pattern and returns its id. category is a category
+// supported by the plugins or empty for all; plugins are the names of the search plugins
+// to use, "all" for all of them or none for the enabled ones
+func (c *Client) StartSearch(pattern string, category string, plugins ...string) (id int, err error) {
+	return c.StartSearchCtx(context.Background(), pattern, category, plugins...)
+}
+
+// StartSearchCtx is StartSearch with a context that controls the lifetime of the request
+func (c *Client) StartSearchCtx(ctx context.Context, pattern string, category string, plugins ...string) (id int, err error) {
+	if category == "" {
+		category = "all"
+	}
+	opts := map[string]string{
+		"pattern":  pattern,
+		"category": category,
+		"plugins":  "enabled",
+	}
+	if len(plugins) > 0 {
+		opts["plugins"] = strings.Join(plugins, "|")
+	}
+	resp, err := c.post(ctx, apiBase+"search/start", opts)
+	if err != nil {
+		return id, describe(err, statusMessages{
+			http.StatusConflict: "too many searches are running",
+		})
+	}
+	defer resp.Body.Close()
+
+	var job SearchJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return id, err
+	}
+	return job.ID, nil
+}
+
+// StopSearch stops a running search, its results are kept until it is deleted
+func (c *Client) StopSearch(id int) error {
+	return c.StopSearchCtx(context.Background(), id)
+}
+
+// StopSearchCtx is StopSearch with a context that controls the lifetime of the request
+func (c *Client) StopSearchCtx(ctx context.Context, id int) error {
+	resp, err := c.post(ctx, apiBase+"search/stop", map[string]string{"id": strconv.Itoa(id)})
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusNotFound: "search job was not found",
+		})
+	}
+	return resp.Body.Close()
+}
+
+// SearchStatus returns the status of a search
+func (c *Client) SearchStatus(id int) (job SearchJob, err error) {
+	return c.SearchStatusCtx(context.Background(), id)
+}
+
+// SearchStatusCtx is SearchStatus with a context that controls the lifetime of the request
+func (c *Client) SearchStatusCtx(ctx context.Context, id int) (job SearchJob, err error) {
+	jobs, err := c.searchStatus(ctx, map[string]string{"id": strconv.Itoa(id)})
+	if err != nil {
+		return job, err
+	}
+	if len(jobs) == 0 {
+		return job, ErrNotFound
+	}
+	return jobs[0], nil
+}
+
+// SearchJobs returns the status of all searches
+func (c *Client) SearchJobs() (jobs []SearchJob, err error) {
+	return c.SearchJobsCtx(context.Background())
+}
+
+// SearchJobsCtx is SearchJobs with a context that controls the lifetime of the request
+func (c *Client) SearchJobsCtx(ctx context.Context) (jobs []SearchJob, err error) {
+	return c.searchStatus(ctx, nil)
+}
+
+// searchStatus returns the status of the search with the id in opts, or of all searches
+func (c *Client) searchStatus(ctx context.Context, opts map[string]string) (jobs []SearchJob, err error) {
+	resp, err := c.get(ctx, apiBase+"search/status", opts)
+	if err != nil {
+		return jobs, describe(err, statusMessages{
+			http.StatusNotFound: "search job was not found",
+		})
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		return jobs, err
+	}
+	return jobs, nil
+}
+
+// SearchResults returns up to limit results of a search from offset, all of them if
+// limit is 0. A negative offset counts from the last result
+func (c *Client) SearchResults(id int, offset int, limit int) (results SearchResults, err error) {
+	return c.SearchResultsCtx(context.Background(), id, offset, limit)
+}
+
+// SearchResultsCtx is SearchResults with a context that controls the lifetime of the request
+func (c *Client) SearchResultsCtx(ctx context.Context, id int, offset int, limit int) (results SearchResults, err error) {
+	opts := map[string]string{
+		"id":     strconv.Itoa(id),
+		"offset": strconv.Itoa(offset),
+		"limit":  strconv.Itoa(limit),
+	}
+	resp, err := c.get(ctx, apiBase+"search/results", opts)
+	if err != nil {
+		return results, describe(err, statusMessages{
+			http.StatusNotFound: "search job was not found",
+			http.StatusConflict: "offset is out of range",
+		})
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// DeleteSearch stops a search if it is running and deletes its results
+func (c *Client) DeleteSearch(id int) error {
+	return c.DeleteSearchCtx(context.Background(), id)
+}
+
+// DeleteSearchCtx is DeleteSearch with a context that controls the lifetime of the request
+func (c *Client) DeleteSearchCtx(ctx context.Context, id int) error {
+	resp, err := c.post(ctx, apiBase+"search/delete", map[string]string{"id": strconv.Itoa(id)})
+	if err != nil {
+		return describe(err, statusMessages{
+			http.StatusNotFound: "search job was not found",
+		})
+	}
+	return resp.Body.Close()
+}
+
+// RunSearch starts a search, collects its results until it stops and deletes it.
+// Arguments are those of StartSearch. If ctx expires first, the search is stopped and
+// deleted and the results found until then are returned with the error of ctx.
+// Failing to delete the search is only reported if the search itself succeeded
+func RunSearch(ctx context.Context, engine SearchEngine, pattern string, category string, plugins ...string) (results []SearchResult, err error) {
+	id, err := engine.StartSearchCtx(ctx, pattern, category, plugins...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// the search must not outlive the call, even when ctx has expired
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), searchCleanupTimeout)
+		defer cancel()
+		if deleteErr := engine.DeleteSearchCtx(cleanupCtx, id); deleteErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete search %d: %w", id, deleteErr)
+		}
+	}()
+
+	ticker := time.NewTicker(searchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// results arrive while the search runs, so only the new ones are fetched
+		page, err := engine.SearchResultsCtx(ctx, id, len(results), 0)
+		if err != nil {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			return results, err
+		}
+		results = append(results, page.Results...)
+		if page.Status == SearchStopped {
+			return results, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package qbt_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// stubbornSearch is a search engine whose searches may never stop on their own
+// and may not be deleted
+type stubbornSearch struct {
+	*qbt.Client
+	endless   bool
+	deleteErr error
+}
+
+func (e stubbornSearch) SearchResultsCtx(ctx context.Context, id int, offset int, limit int) (qbt.SearchResults, error) {
+	results, err := e.Client.SearchResultsCtx(ctx, id, offset, limit)
+	if e.endless {
+		results.Status = qbt.SearchRunning
+	}
+	return results, err
+}
+
+func (e stubbornSearch) DeleteSearchCtx(ctx context.Context, id int) error {
+	if e.deleteErr != nil {
+		return e.deleteErr
+	}
+	return e.Client.DeleteSearchCtx(ctx, id)
+}
+
+// newSearchServer returns a fake whose plugins find two Ubuntu releases and a Debian one
+func newSearchServer() *qbttest.Server {
+	srv := qbttest.NewServer()
+	srv.AddSearchResults(
+		qbt.SearchResult{FileName: "ubuntu-24.04-desktop-amd64.iso", FileSize: 6000},
+		qbt.SearchResult{FileName: "debian-12-amd64-netinst.iso", FileSize: 650},
+		qbt.SearchResult{FileName: "ubuntu-24.04-live-server-amd64.iso", FileSize: 2700},
+	)
+	return srv
+}
+
+func TestRunSearch(t *testing.T) {
+	srv := newSearchServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	results, err := qbt.RunSearch(context.Background(), client, "ubuntu amd64", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, result := range results {
+		names = append(names, result.FileName)
+	}
+	if want := []string{"ubuntu-24.04-desktop-amd64.iso", "ubuntu-24.04-live-server-amd64.iso"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("found %q, want %q", names, want)
+	}
+
+	if jobs, err := client.SearchJobs(); err != nil || len(jobs) != 0 {
+		t.Errorf("searches left behind: %v, %v", jobs, err)
+	}
+}
+
+func TestRunSearchContextDone(t *testing.T) {
+	srv := newSearchServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	results, err := qbt.RunSearch(ctx, stubbornSearch{Client: client, endless: true}, "debian", "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("got %v, want the result found before the deadline", results)
+	}
+	// the search is deleted although ctx expired
+	if jobs, err := client.SearchJobs(); err != nil || len(jobs) != 0 {
+		t.Errorf("searches left behind: %v, %v", jobs, err)
+	}
+}
+
+func TestStartSearchUnsupported(t *testing.T) {
+	srv := qbttest.NewServer(qbttest.WithWebAPIVersion("2.0.0"))
+	defer srv.Close()
+
+	// the search API arrived with 2.1.1
+	if _, err := srv.Client().StartSearch("ubuntu", ""); !errors.Is(err, qbt.ErrUnsupported) {
+		t.Fatalf("got %v, want ErrUnsupported", err)
+	}
+}
+
+func TestNoRetryOfSearchStart(t *testing.T) {
+	client, server := newRestartingClient(t, map[string]int{"search/start": 1})
+
+	// a search started before the server failed would run twice
+	_, err := client.StartSearch("ubuntu", "")
+	var apiErr *qbt.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v, want a 503 APIError", err)
+	}
+	if n := server.count("search/start"); n != 1 {
+		t.Errorf("sent search/start %d times, want 1", n)
+	}
+}
+
+func TestRunSearchDeleteFails(t *testing.T) {
+	srv := newSearchServer()
+	defer srv.Close()
+
+	deleteErr := errors.New("delete failed")
+	_, err := qbt.RunSearch(context.Background(), stubbornSearch{Client: srv.Client(), deleteErr: deleteErr}, "debian", "")
+	if !errors.Is(err, deleteErr) {
+		t.Fatalf("got %v, want the error of deleting the search", err)
+	}
+}
@@ -0,0 +1,173 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnmarshalJSON decodes a category, which is only a name before Web API 2.6
+func (c *SearchCategory) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*c = SearchCategory{ID: name, Name: name}
+		return nil
+	}
+
+	type category SearchCategory
+	return json.Unmarshal(data, (*category)(c))
+}
+
+// Search Plugin Endpoints
+
+// SearchPlugins returns the installed search plugins
+func (c *Client) SearchPlugins() (plugins []SearchPlugin, err error) {
+	return c.SearchPluginsCtx(context.Background())
+}
+
+// SearchPluginsCtx is SearchPlugins with a context that controls the lifetime of the request
+func (c *Client) SearchPluginsCtx(ctx context.Context) (plugins []SearchPlugin, err error) {
+	resp, err := c.get(ctx, apiBase+"search/plugins", nil)
+	if err != nil {
+		return plugins, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&plugins); err != nil {
+		return plugins, err
+	}
+	return plugins, nil
+}
+
+// InstallSearchPlugins installs search plugins from urls or paths on the qbittorrent
+// host. qbittorrent installs them in the background, they are listed by SearchPlugins
+// once installed
+func (c *Client) InstallSearchPlugins(sources []string) error {
+	return c.InstallSearchPluginsCtx(context.Background(), sources)
+}
+
+// InstallSearchPluginsCtx is InstallSearchPlugins with a context that controls the lifetime of the request
+func (c *Client) InstallSearchPluginsCtx(ctx context.Context, sources []string) error {
+	resp, err := c.post(ctx, apiBase+"search/installPlugin", map[string]string{"sources": strings.Join(sources, "|")})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// UninstallSearchPlugins uninstalls the search plugins with the names
+func (c *Client) UninstallSearchPlugins(names []string) error {
+	return c.UninstallSearchPluginsCtx(context.Background(), names)
+}
+
+// UninstallSearchPluginsCtx is UninstallSearchPlugins with a context that controls the lifetime of the request
+func (c *Client) UninstallSearchPluginsCtx(ctx context.Context, names []string) error {
+	resp, err := c.post(ctx, apiBase+"search/uninstallPlugin", map[string]string{"names": strings.Join(names, "|")})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// EnableSearchPlugins enables or disables the search plugins with the names
+func (c *Client) EnableSearchPlugins(names []string, enable bool) error {
+	return c.EnableSearchPluginsCtx(context.Background(), names, enable)
+}
+
+// EnableSearchPluginsCtx is EnableSearchPlugins with a context that controls the lifetime of the request
+func (c *Client) EnableSearchPluginsCtx(ctx context.Context, names []string, enable bool) error {
+	opts := map[string]string{
+		"names":  strings.Join(names, "|"),
+		"enable": strconv.FormatBool(enable),
+	}
+	resp, err := c.post(ctx, apiBase+"search/enablePlugin", opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// UpdateSearchPlugins updates the search plugins to their latest versions in the background
+func (c *Client) UpdateSearchPlugins() error {
+	return c.UpdateSearchPluginsCtx(context.Background())
+}
+
+// UpdateSearchPluginsCtx is UpdateSearchPlugins with a context that controls the lifetime of the request
+func (c *Client) UpdateSearchPluginsCtx(ctx context.Context) error {
+	resp, err := c.post(ctx, apiBase+"search/updatePlugins", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ProvisionSearchPlugins makes sure the search plugins with the given names are installed
+// and enabled. Missing plugins are installed from their source, a url or a path on the
+// qbittorrent host, and waited for until ctx expires
+func ProvisionSearchPlugins(ctx context.Context, plugins SearchPluginManager, sources map[string]string) error {
+	installed, err := plugins.SearchPluginsCtx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list search plugins: %w", err)
+	}
+
+	missing := missingSearchPlugins(installed, sources)
+	if len(missing) > 0 {
+		var install []string
+		for _, name := range missing {
+			install = append(install, sources[name])
+		}
+		if err := plugins.InstallSearchPluginsCtx(ctx, install); err != nil {
+			return fmt.Errorf("failed to install search plugins: %w", err)
+		}
+	}
+
+	ticker := time.NewTicker(searchPollInterval)
+	defer ticker.Stop()
+	for len(missing) > 0 {
+		if installed, err = plugins.SearchPluginsCtx(ctx); err != nil {
+			return fmt.Errorf("failed to list search plugins: %w", err)
+		}
+		if missing = missingSearchPlugins(installed, sources); len(missing) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("search plugins %s not installed: %w", strings.Join(missing, ", "), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	var disabled []string
+	for _, plugin := range installed {
+		if _, wanted := sources[plugin.Name]; wanted && !plugin.Enabled {
+			disabled = append(disabled, plugin.Name)
+		}
+	}
+	if len(disabled) > 0 {
+		if err := plugins.EnableSearchPluginsCtx(ctx, disabled, true); err != nil {
+			return fmt.Errorf("failed to enable search plugins: %w", err)
+		}
+	}
+	return nil
+}
+
+// missingSearchPlugins returns the sorted names of the wanted plugins that are not installed
+func missingSearchPlugins(installed []SearchPlugin, wanted map[string]string) []string {
+	have := map[string]bool{}
+	for _, plugin := range installed {
+		have[plugin.Name] = true
+	}
+	var missing []string
+	for name := range wanted {
+		if !have[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
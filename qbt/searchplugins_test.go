@@ -0,0 +1,146 @@
+package qbt_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// pluginStates returns whether each installed plugin is enabled by its name
+func pluginStates(t *testing.T, client *qbt.Client) map[string]bool {
+	t.Helper()
+	plugins, err := client.SearchPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := map[string]bool{}
+	for _, plugin := range plugins {
+		states[plugin.Name] = plugin.Enabled
+	}
+	return states
+}
+
+func TestSearchPlugins(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	sources := []string{"https://example.com/plugins/eztv.py", "https://example.com/plugins/limetorrents.py"}
+	if err := client.InstallSearchPlugins(sources); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.EnableSearchPlugins([]string{"eztv"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"eztv": false, "limetorrents": true}; !reflect.DeepEqual(pluginStates(t, client), want) {
+		t.Errorf("plugins are %v, want %v", pluginStates(t, client), want)
+	}
+
+	if err := client.UpdateSearchPlugins(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UninstallSearchPlugins([]string{"eztv"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"limetorrents": true}; !reflect.DeepEqual(pluginStates(t, client), want) {
+		t.Errorf("plugins are %v after uninstalling eztv, want %v", pluginStates(t, client), want)
+	}
+}
+
+func TestSearchCategoryLegacyName(t *testing.T) {
+	var plugin qbt.SearchPlugin
+	data := `{"name":"eztv","supportedCategories":["tv",{"id":"movies","name":"Movies"}]}`
+	if err := json.Unmarshal([]byte(data), &plugin); err != nil {
+		t.Fatal(err)
+	}
+	want := []qbt.SearchCategory{{ID: "tv", Name: "tv"}, {ID: "movies", Name: "Movies"}}
+	if !reflect.DeepEqual(plugin.SupportedCategories, want) {
+		t.Errorf("categories are %v, want %v", plugin.SupportedCategories, want)
+	}
+}
+
+// backgroundInstall is a client for a qbittorrent that takes a while to install plugins,
+// leaving newly installed plugins out of the first listings after installing them
+type backgroundInstall struct {
+	*qbt.Client
+	hidden map[string]bool
+	delay  int // listings still leaving out the hidden plugins
+}
+
+func (b *backgroundInstall) SearchPluginsCtx(ctx context.Context) ([]qbt.SearchPlugin, error) {
+	plugins, err := b.Client.SearchPluginsCtx(ctx)
+	if err != nil || b.delay == 0 {
+		return plugins, err
+	}
+	b.delay--
+	var listed []qbt.SearchPlugin
+	for _, plugin := range plugins {
+		if !b.hidden[plugin.Name] {
+			listed = append(listed, plugin)
+		}
+	}
+	return listed, nil
+}
+
+func TestProvisionSearchPlugins(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	srv.AddSearchPlugin(qbt.SearchPlugin{Name: "limetorrents", Enabled: false})
+	srv.AddSearchPlugin(qbt.SearchPlugin{Name: "legittorrents", Enabled: true})
+
+	// eztv is left out of the first two listings, so provisioning has to wait for it once
+	plugins := &backgroundInstall{Client: client, hidden: map[string]bool{"eztv": true}, delay: 2}
+	sources := map[string]string{
+		"eztv":         "https://example.com/plugins/eztv.py",
+		"limetorrents": "https://example.com/plugins/limetorrents.py",
+	}
+	if err := qbt.ProvisionSearchPlugins(context.Background(), plugins, sources); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"eztv": true, "limetorrents": true, "legittorrents": true}
+	if got := pluginStates(t, client); !reflect.DeepEqual(got, want) {
+		t.Errorf("plugins are %v, want %v", got, want)
+	}
+}
+
+func TestProvisionSearchPluginsTimeout(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// the fake only installs python files, so the plugin never appears
+	sources := map[string]string{"eztv": "https://example.com/plugins/eztv.zip"}
+	err := qbt.ProvisionSearchPlugins(ctx, srv.Client(), sources)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "eztv") {
+		t.Fatalf("got %v, want eztv not installed before the deadline", err)
+	}
+}
+
+func TestNoRetryOfSearchPluginInstalls(t *testing.T) {
+	client, server := newRestartingClient(t, map[string]int{"search/installPlugin": 1, "search/updatePlugins": 1})
+
+	// plugins are downloaded and installed in the background, so a request that failed
+	// may still have started an installation
+	var apiErr *qbt.APIError
+	if err := client.InstallSearchPlugins([]string{"https://example.com/plugins/eztv.py"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v installing a plugin, want a 503 APIError", err)
+	}
+	if err := client.UpdateSearchPlugins(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v updating the plugins, want a 503 APIError", err)
+	}
+	if n := server.count("search/installPlugin") + server.count("search/updatePlugins"); n != 2 {
+		t.Errorf("sent %d requests, want 1 for each", n)
+	}
+}
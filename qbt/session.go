@@ -0,0 +1,142 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// sessionFile is the on-disk form of a saved session
+type sessionFile struct {
+	URL     string          `json:"url"`
+	Cookies []sessionCookie `json:"cookies"`
+}
+
+// sessionCookie is a cookie of a saved session
+type sessionCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WithSessionFile makes Login reuse the session saved in fileName while it is still
+// valid and save the session it creates otherwise, so that short lived processes
+// don't have to log in every time they start. A saved session that cannot be restored
+// is overwritten by the new one
+func WithSessionFile(fileName string) Option {
+	return func(o *options) {
+		o.sessionFile = fileName
+	}
+}
+
+// SaveSession writes the session cookies of the client to fileName, readable only by the current user
+func (c *Client) SaveSession(fileName string) error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse client url: %w", err)
+	}
+
+	session := sessionFile{URL: c.URL}
+	for _, cookie := range c.Jar.Cookies(u) {
+		session.Cookies = append(session.Cookies, sessionCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	// write to a temporary file first so a crash never leaves a truncated session behind
+	file, err := os.CreateTemp(filepath.Dir(fileName), ".qbt-session-*")
+	if err != nil {
+		return fmt.Errorf("error creating session file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return fmt.Errorf("error setting session file permissions: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing session file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing session file: %w", err)
+	}
+	if err := os.Rename(file.Name(), fileName); err != nil {
+		return fmt.Errorf("error replacing session file: %w", err)
+	}
+	return nil
+}
+
+// RestoreSession loads the session cookies saved in fileName and checks that qbittorrent
+// still accepts them. It returns false without an error when there is no saved session
+// or the saved session has expired
+func (c *Client) RestoreSession(ctx context.Context, fileName string) (bool, error) {
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("error reading session file: %w", err)
+	}
+
+	var session sessionFile
+	if err := json.Unmarshal(data, &session); err != nil {
+		return false, fmt.Errorf("failed to decode session file: %w", err)
+	}
+	// a session is only valid for the instance that created it
+	if session.URL != c.URL {
+		return false, nil
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse client url: %w", err)
+	}
+	cookies := make([]*http.Cookie, 0, len(session.Cookies))
+	for _, cookie := range session.Cookies {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/"})
+	}
+	c.Jar.SetCookies(u, cookies)
+
+	valid, err := c.validateSession(ctx)
+	if err != nil {
+		return false, err
+	}
+	c.Authenticated = valid
+	return valid, nil
+}
+
+// validateSession checks whether the current session cookie is accepted with a cheap request.
+// It bypasses re-authentication since it is used while logging in
+func (c *Client) validateSession(ctx context.Context) (bool, error) {
+	resp, err := c.send(apiBase+"app/version", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+apiBase+"app/version", nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(req)
+		return req, nil
+	})
+	if errors.Is(err, ErrForbidden) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, resp.Body.Close()
+}
+
+// removeSession deletes the saved session after logging out
+func (c *Client) removeSession() error {
+	if c.sessionFile == "" {
+		return nil
+	}
+	if err := os.Remove(c.sessionFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing session file: %w", err)
+	}
+	return nil
+}
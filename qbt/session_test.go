@@ -0,0 +1,79 @@
+package qbt_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+func TestLoginWithSessionFile(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		setup  func(t *testing.T, srv *qbttest.Server, fileName string)
+		logins int
+	}{
+		{"missing", func(t *testing.T, srv *qbttest.Server, fileName string) {}, 1},
+		{"valid", func(t *testing.T, srv *qbttest.Server, fileName string) {
+			srv.Client(qbt.WithSessionFile(fileName))
+		}, 0},
+		{"expired", func(t *testing.T, srv *qbttest.Server, fileName string) {
+			srv.Client(qbt.WithSessionFile(fileName))
+			srv.ExpireSessions()
+		}, 1},
+		{"corrupt", func(t *testing.T, srv *qbttest.Server, fileName string) {
+			if err := os.WriteFile(fileName, []byte(`{"url":`), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := qbttest.NewServer()
+			defer srv.Close()
+
+			fileName := filepath.Join(t.TempDir(), "session.json")
+			tc.setup(t, srv, fileName)
+
+			transport := &countingTransport{}
+			client := qbt.NewClient(srv.URL, qbt.WithSessionFile(fileName), qbt.WithTransport(transport))
+			if err := client.Login(qbttest.DefaultUsername, qbttest.DefaultPassword); err != nil {
+				t.Fatal(err)
+			}
+			if n := transport.count("auth/login"); n != tc.logins {
+				t.Errorf("logged in %d times, want %d", n, tc.logins)
+			}
+			if _, err := client.ApplicationVersion(); err != nil {
+				t.Fatalf("request after login: %v", err)
+			}
+
+			// the file now holds the session in use, so the next process can reuse it
+			transport.reset()
+			next := qbt.NewClient(srv.URL, qbt.WithSessionFile(fileName), qbt.WithTransport(transport))
+			if err := next.Login(qbttest.DefaultUsername, qbttest.DefaultPassword); err != nil {
+				t.Fatal(err)
+			}
+			if n := transport.count("auth/login"); n != 0 {
+				t.Errorf("logged in %d times with the saved session, want 0", n)
+			}
+		})
+	}
+}
+
+func TestLoginWithSessionFileFails(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	fileName := filepath.Join(t.TempDir(), "session.json")
+	if err := os.WriteFile(fileName, []byte("corrupt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	client := qbt.NewClient(srv.URL, qbt.WithSessionFile(fileName))
+	if err := client.Login(qbttest.DefaultUsername, "wrong"); err == nil {
+		t.Fatal("login with a wrong password succeeded")
+	}
+	if data, err := os.ReadFile(fileName); err != nil || string(data) != "corrupt" {
+		t.Errorf("failed login replaced the session file with %q, %v", data, err)
+	}
+}
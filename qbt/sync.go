@@ -0,0 +1,249 @@
+package qbt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// fields is an object of a sync response kept in its raw form, so partial updates
+// can be merged into it without losing the values they leave out
+type fields map[string]json.RawMessage
+
+// merge copies the fields of update over f
+func (f fields) merge(update fields) {
+	for key, value := range update {
+		f[key] = value
+	}
+}
+
+// decode converts the merged fields into v
+func (f fields) decode(v interface{}) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// mainDataUpdate is a sync/maindata response in the raw form SyncState merges
+type mainDataUpdate struct {
+	Rid               int               `json:"rid"`
+	FullUpdate        bool              `json:"full_update"`
+	Torrents          map[string]fields `json:"torrents"`
+	TorrentsRemoved   []string          `json:"torrents_removed"`
+	Categories        map[string]fields `json:"categories"`
+	CategoriesRemoved []string          `json:"categories_removed"`
+	Tags              []string          `json:"tags"`
+	TagsRemoved       []string          `json:"tags_removed"`
+	ServerState       fields            `json:"server_state"`
+}
+
+// SyncSnapshot is the complete state of qbittorrent as of a rid.
+// Snapshots are never modified after they are returned and may be shared freely
+type SyncSnapshot struct {
+	Rid         int
+	Torrents    map[string]TorrentInfo // by hash
+	Categories  map[string]Category    // by name
+	Tags        []string
+	ServerState ServerState
+}
+
+// SyncState keeps a local copy of the qbittorrent state up to date by applying the
+// incremental updates of sync/maindata. It is safe for concurrent use
+type SyncState struct {
+	reader SyncReader
+
+	// updateMu serializes updates so each one applies on top of the previous rid
+	updateMu sync.Mutex
+
+	mu          sync.RWMutex
+	rid         int
+	torrents    map[string]fields
+	categories  map[string]fields
+	tags        map[string]bool
+	serverState fields
+	snapshot    SyncSnapshot
+}
+
+// NewSyncState returns a SyncState reading from a Client or a fake of it. It is
+// empty until the first Update
+func NewSyncState(reader SyncReader) *SyncState {
+	s := &SyncState{reader: reader}
+	s.reset()
+	return s
+}
+
+// Update fetches the changes since the last update and applies them. If the
+// changes cannot be decoded none of them are applied and the error is returned
+func (s *SyncState) Update() (SyncSnapshot, error) {
+	return s.UpdateCtx(context.Background())
+}
+
+// UpdateCtx is Update with a context that controls the lifetime of the request
+func (s *SyncState) UpdateCtx(ctx context.Context) (SyncSnapshot, error) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	update, err := fetchMainDataUpdate(ctx, s.reader, s.Rid())
+	if err != nil {
+		return s.Snapshot(), err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.apply(update); err != nil {
+		return s.snapshot, fmt.Errorf("failed to apply sync/maindata rid %d: %w", update.Rid, err)
+	}
+	return s.snapshot, nil
+}
+
+// Snapshot returns the state as of the last update
+func (s *SyncState) Snapshot() SyncSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.snapshot
+}
+
+// Rid returns the response id of the last update, 0 before the first
+func (s *SyncState) Rid() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.rid
+}
+
+// Reset discards the local state so the next update fetches everything again
+func (s *SyncState) Reset() {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset()
+}
+
+// reset empties the state; s.mu must be held
+func (s *SyncState) reset() {
+	s.rid = 0
+	s.torrents = map[string]fields{}
+	s.categories = map[string]fields{}
+	s.tags = map[string]bool{}
+	s.serverState = fields{}
+	s.snapshot = SyncSnapshot{
+		Torrents:   map[string]TorrentInfo{},
+		Categories: map[string]Category{},
+		Tags:       []string{},
+	}
+}
+
+// apply merges update into the state and builds the next snapshot; s.mu must be held.
+// The previous snapshot caches the decoded torrents and categories, so only those the
+// update touched are decoded again. Nothing is changed if any of them fails to decode
+func (s *SyncState) apply(update mainDataUpdate) error {
+	torrents, categories, tags, serverState := s.torrents, s.categories, s.tags, s.serverState
+	previous := s.snapshot
+	if update.FullUpdate {
+		torrents, categories, tags, serverState = map[string]fields{}, map[string]fields{}, map[string]bool{}, fields{}
+		previous = SyncSnapshot{}
+	}
+
+	next := SyncSnapshot{
+		Rid:        update.Rid,
+		Torrents:   make(map[string]TorrentInfo, len(previous.Torrents)+len(update.Torrents)),
+		Categories: make(map[string]Category, len(previous.Categories)+len(update.Categories)),
+	}
+	for hash, torrent := range previous.Torrents {
+		next.Torrents[hash] = torrent
+	}
+	for name, category := range previous.Categories {
+		next.Categories[name] = category
+	}
+
+	// the raw state is only changed once everything decoded
+	mergedTorrents := make(map[string]fields, len(update.Torrents))
+	for hash, changed := range update.Torrents {
+		merged := fields{}
+		merged.merge(torrents[hash])
+		merged.merge(changed)
+
+		var torrent TorrentInfo
+		if err := merged.decode(&torrent); err != nil {
+			return fmt.Errorf("failed to decode torrent %s: %w", hash, err)
+		}
+		// sync/maindata identifies torrents by the map key
+		torrent.Hash = hash
+		mergedTorrents[hash] = merged
+		next.Torrents[hash] = torrent
+	}
+	mergedCategories := make(map[string]fields, len(update.Categories))
+	for name, changed := range update.Categories {
+		merged := fields{}
+		merged.merge(categories[name])
+		merged.merge(changed)
+
+		var category Category
+		if err := merged.decode(&category); err != nil {
+			return fmt.Errorf("failed to decode category %q: %w", name, err)
+		}
+		if category.Name == "" {
+			category.Name = name
+		}
+		mergedCategories[name] = merged
+		next.Categories[name] = category
+	}
+	mergedServerState := fields{}
+	mergedServerState.merge(serverState)
+	mergedServerState.merge(update.ServerState)
+	if err := mergedServerState.decode(&next.ServerState); err != nil {
+		return fmt.Errorf("failed to decode server state: %w", err)
+	}
+
+	for hash, merged := range mergedTorrents {
+		torrents[hash] = merged
+	}
+	for _, hash := range update.TorrentsRemoved {
+		delete(torrents, hash)
+		delete(next.Torrents, hash)
+	}
+	for name, merged := range mergedCategories {
+		categories[name] = merged
+	}
+	for _, name := range update.CategoriesRemoved {
+		delete(categories, name)
+		delete(next.Categories, name)
+	}
+	for _, tag := range update.Tags {
+		tags[tag] = true
+	}
+	for _, tag := range update.TagsRemoved {
+		delete(tags, tag)
+	}
+	next.Tags = make([]string, 0, len(tags))
+	for tag := range tags {
+		next.Tags = append(next.Tags, tag)
+	}
+	sort.Strings(next.Tags)
+
+	s.rid = update.Rid
+	s.torrents, s.categories, s.tags, s.serverState = torrents, categories, tags, mergedServerState
+	s.snapshot = next
+	return nil
+}
+
+// fetchMainDataUpdate fetches sync/maindata in the raw form SyncState merges
+func fetchMainDataUpdate(ctx context.Context, reader SyncReader, rid int) (update mainDataUpdate, err error) {
+	data, err := reader.RawMainDataCtx(ctx, strconv.Itoa(rid))
+	if err != nil {
+		return update, err
+	}
+	if err := json.Unmarshal(data, &update); err != nil {
+		return update, fmt.Errorf("failed to decode sync/maindata: %w", err)
+	}
+	return update, nil
+}
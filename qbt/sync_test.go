@@ -0,0 +1,170 @@
+package qbt_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+const (
+	hashA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	hashB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func TestSyncStatePartialUpdates(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	srv.AddTorrent(qbt.TorrentInfo{Hash: hashA, Name: "a", Category: "movies", Tags: "keep"})
+	srv.AddTorrent(qbt.TorrentInfo{Hash: hashB, Name: "b"})
+	if err := client.CreateCategory("old", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateTags([]string{"old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := qbt.NewSyncState(client)
+	first, err := state.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Torrents) != 2 || len(first.Categories) != 2 || !reflect.DeepEqual(first.Tags, []string{"keep", "old"}) {
+		t.Fatalf("first snapshot has torrents %v, categories %v and tags %v", first.Torrents, first.Categories, first.Tags)
+	}
+
+	srv.UpdateTorrent(hashA, func(info *qbt.TorrentInfo) { info.Name = "renamed" })
+	if err := client.Delete([]string{hashB}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteCategories([]string{"old"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DeleteTags([]string{"old"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateTags([]string{"new"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the response only holds what changed, the rest must come from the merged state
+	data, err := client.RawMainData(strconv.Itoa(first.Rid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var update struct {
+		FullUpdate        bool                                  `json:"full_update"`
+		Torrents          map[string]map[string]json.RawMessage `json:"torrents"`
+		TorrentsRemoved   []string                              `json:"torrents_removed"`
+		CategoriesRemoved []string                              `json:"categories_removed"`
+		Tags              []string                              `json:"tags"`
+		TagsRemoved       []string                              `json:"tags_removed"`
+	}
+	if err := json.Unmarshal(data, &update); err != nil {
+		t.Fatal(err)
+	}
+	if update.FullUpdate {
+		t.Fatalf("got a full update: %s", data)
+	}
+	if _, ok := update.Torrents[hashA]["category"]; ok || update.Torrents[hashA]["name"] == nil {
+		t.Errorf("torrent update is not partial: %s", data)
+	}
+	if !reflect.DeepEqual(update.TorrentsRemoved, []string{hashB}) ||
+		!reflect.DeepEqual(update.CategoriesRemoved, []string{"old"}) ||
+		!reflect.DeepEqual(update.Tags, []string{"new"}) ||
+		!reflect.DeepEqual(update.TagsRemoved, []string{"old"}) {
+		t.Errorf("unexpected removals in %s", data)
+	}
+
+	second, err := state.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Rid <= first.Rid {
+		t.Errorf("rid went from %d to %d", first.Rid, second.Rid)
+	}
+	a, ok := second.Torrents[hashA]
+	if !ok || a.Name != "renamed" || a.Category != "movies" || a.Tags != "keep" {
+		t.Errorf("torrent a is %+v, want it renamed with its category and tags kept", a)
+	}
+	if _, ok := second.Torrents[hashB]; ok {
+		t.Error("removed torrent b is still there")
+	}
+	if _, ok := second.Categories["old"]; ok || len(second.Categories) != 1 {
+		t.Errorf("categories are %v, want movies only", second.Categories)
+	}
+	if !reflect.DeepEqual(second.Tags, []string{"keep", "new"}) {
+		t.Errorf("tags are %v, want keep and new", second.Tags)
+	}
+
+	// earlier snapshots are not changed by later updates
+	if first.Torrents[hashA].Name != "a" || len(first.Torrents) != 2 {
+		t.Errorf("first snapshot changed to %v", first.Torrents)
+	}
+}
+
+// scriptedMainData answers sync/maindata with canned responses, one per request
+type scriptedMainData struct {
+	qbt.SyncReader
+	responses []string
+	rids      []string
+}
+
+func (s *scriptedMainData) RawMainDataCtx(ctx context.Context, rid string) (json.RawMessage, error) {
+	s.rids = append(s.rids, rid)
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return json.RawMessage(response), nil
+}
+
+func TestSyncStateDecodeError(t *testing.T) {
+	reader := &scriptedMainData{responses: []string{
+		`{"rid":1,"full_update":true,"torrents":{"` + hashA + `":{"name":"a","state":"downloading","progress":0.5},"` + hashB + `":{"name":"b"}}}`,
+		`{"rid":2,"torrents":{"` + hashA + `":{"state":"uploading","progress":"done"},"` + hashB + `":{"name":"renamed"}}}`,
+		`{"rid":3,"torrents":{"` + hashA + `":{"state":"uploading","progress":1}}}`,
+		`{"rid":4,"full_update":true,"torrents":{"` + hashB + `":{"name":"b"}}}`,
+	}}
+	state := qbt.NewSyncState(reader)
+	first, err := state.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a value that does not decode must not be reported as a zero value, nor apply the rest of its update
+	snapshot, err := state.Update()
+	if err == nil {
+		t.Fatal("update with an undecodable progress succeeded")
+	}
+	if !reflect.DeepEqual(snapshot, first) || state.Rid() != 1 {
+		t.Errorf("failed update changed the state to %+v at rid %d", snapshot, state.Rid())
+	}
+
+	third, err := state.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reader.rids, []string{"0", "1", "1"}) {
+		t.Errorf("requested rids %v, want the failed update to be fetched again", reader.rids)
+	}
+	if a := third.Torrents[hashA]; a.Name != "a" || a.State != "uploading" || a.Progress != 1 {
+		t.Errorf("torrent a is %+v", a)
+	}
+	if b := third.Torrents[hashB]; b.Name != "b" {
+		t.Errorf("torrent b is %+v, want it untouched", b)
+	}
+
+	// a full update replaces the cached torrents
+	fourth, err := state.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fourth.Torrents[hashA]; ok || len(fourth.Torrents) != 1 {
+		t.Errorf("torrents after a full update are %v, want b only", fourth.Torrents)
+	}
+}
@@ -0,0 +1,150 @@
+package qbt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrCertificatePinMismatch is returned when no certificate presented by the server matches a configured pin
+var ErrCertificatePinMismatch = errors.New("server certificate does not match any pinned fingerprint")
+
+// LoadCACertFile reads a PEM encoded CA bundle into a certificate pool for WithRootCAs
+func LoadCACertFile(fileName string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA bundle: %w", err)
+	}
+	return CACertPoolFromPEM(pem)
+}
+
+// CACertPoolFromPEM parses PEM encoded CA certificates into a certificate pool for WithRootCAs
+func CACertPoolFromPEM(pem []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle")
+	}
+	return pool, nil
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of a certificate in the
+// hex form accepted by WithCertificatePins
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// WithTLSConfig uses a copy of config as the base of the client's TLS settings
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = config.Clone()
+	}
+}
+
+// WithRootCAs verifies the server certificate against pool instead of the system roots,
+// which is needed for qbittorrent instances using self-signed certificates
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(o *options) {
+		o.rootCAs = pool
+	}
+}
+
+// WithClientCertificate presents cert to servers that require mutual TLS
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(o *options) {
+		o.clientCerts = append(o.clientCerts, cert)
+	}
+}
+
+// WithCertificatePins only accepts servers presenting a certificate with one of the given
+// SHA-256 fingerprints. Fingerprints are hex encoded and may contain colons,
+// as printed by "openssl x509 -noout -fingerprint -sha256"
+func WithCertificatePins(fingerprints ...string) Option {
+	return func(o *options) {
+		o.pins = append(o.pins, fingerprints...)
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server certificate.
+// Unless certificate pins are configured this leaves the connection open to
+// man-in-the-middle attacks, so a warning is logged when the client is created
+func WithInsecureSkipVerify() Option {
+	return func(o *options) {
+		o.insecure = true
+	}
+}
+
+// hasTLS reports whether any TLS option was set
+func (o *options) hasTLS() bool {
+	return o.tlsConfig != nil || o.rootCAs != nil || len(o.clientCerts) > 0 || len(o.pins) > 0 || o.insecure
+}
+
+// buildTLSConfig combines the TLS options into a single configuration
+func (o *options) buildTLSConfig() (*tls.Config, error) {
+	config := o.tlsConfig
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if o.rootCAs != nil {
+		config.RootCAs = o.rootCAs
+	}
+	config.Certificates = append(config.Certificates, o.clientCerts...)
+
+	if o.insecure {
+		config.InsecureSkipVerify = true
+		if len(o.pins) == 0 {
+			log.Printf("go-qbittorrent: TLS certificate verification is disabled, connections are not protected against man-in-the-middle attacks")
+		}
+	}
+
+	if len(o.pins) > 0 {
+		pins, err := parsePins(o.pins)
+		if err != nil {
+			return nil, err
+		}
+		verify := config.VerifyConnection
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if verify != nil {
+				if err := verify(state); err != nil {
+					return err
+				}
+			}
+			return verifyPins(state, pins)
+		}
+	}
+
+	return config, nil
+}
+
+// parsePins decodes hex encoded SHA-256 fingerprints
+func parsePins(fingerprints []string) ([][]byte, error) {
+	pins := make([][]byte, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		pin, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+		if err != nil || len(pin) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 certificate pin %q", fingerprint)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// verifyPins checks that one of the certificates presented by the server matches a pin
+func verifyPins(state tls.ConnectionState, pins [][]byte) error {
+	for _, cert := range state.PeerCertificates {
+		sum := sha256.Sum256(cert.Raw)
+		for _, pin := range pins {
+			if bytes.Equal(sum[:], pin) {
+				return nil
+			}
+		}
+	}
+	return ErrCertificatePinMismatch
+}
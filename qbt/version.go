@@ -0,0 +1,194 @@
+package qbt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned for endpoints that the connected qbittorrent does not provide
+var ErrUnsupported = errors.New("not supported by this qbittorrent version")
+
+// APIVersion is a parsed Web API version such as 2.11.2
+type APIVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Web API versions that changed the endpoints the client uses
+var (
+	apiV2_1_0  = APIVersion{2, 1, 0}
+	apiV2_1_1  = APIVersion{2, 1, 1}
+	apiV2_2_0  = APIVersion{2, 2, 0}
+	apiV2_3_0  = APIVersion{2, 3, 0}
+	apiV2_4_0  = APIVersion{2, 4, 0}
+	apiV2_5_1  = APIVersion{2, 5, 1}
+	apiV2_8_0  = APIVersion{2, 8, 0}
+	apiV2_9_1  = APIVersion{2, 9, 1}
+	apiV2_9_2  = APIVersion{2, 9, 2}
+	apiV2_11_0 = APIVersion{2, 11, 0}
+)
+
+// endpointVersions holds the Web API version that introduced an endpoint,
+// for endpoints that are newer than the 2.0 API
+var endpointVersions = map[string]APIVersion{
+	apiBase + "transfer/banPeers":     apiV2_3_0,
+	apiBase + "torrents/editCategory": apiV2_1_0,
+	apiBase + "torrents/addTags":      apiV2_3_0,
+	apiBase + "torrents/removeTags":   apiV2_3_0,
+	apiBase + "torrents/tags":         apiV2_3_0,
+	apiBase + "torrents/createTags":   apiV2_3_0,
+	apiBase + "torrents/deleteTags":   apiV2_3_0,
+	apiBase + "torrents/renameFile":   apiV2_4_0,
+	apiBase + "torrents/renameFolder": apiV2_8_0,
+	apiBase + "torrents/stop":         apiV2_11_0,
+	apiBase + "torrents/start":        apiV2_11_0,
+	apiBase + "rss/refreshItem":       apiV2_2_0,
+	apiBase + "rss/markAsRead":        apiV2_5_1,
+	apiBase + "rss/setFeedURL":        apiV2_9_1,
+	apiBase + "rss/matchingArticles":  apiV2_5_1,
+
+	// the search API arrived with 2.1.1
+	apiBase + "search/start":   apiV2_1_1,
+	apiBase + "search/stop":    apiV2_1_1,
+	apiBase + "search/status":  apiV2_1_1,
+	apiBase + "search/results": apiV2_1_1,
+	apiBase + "search/delete":  apiV2_1_1,
+
+	// so did managing search plugins
+	apiBase + "search/plugins":         apiV2_1_1,
+	apiBase + "search/installPlugin":   apiV2_1_1,
+	apiBase + "search/uninstallPlugin": apiV2_1_1,
+	apiBase + "search/enablePlugin":    apiV2_1_1,
+	apiBase + "search/updatePlugins":   apiV2_1_1,
+
+	// parameters added to existing endpoints use the endpoint#parameter form
+	apiBase + "torrents/setShareLimits#inactiveSeedingTimeLimit": apiV2_9_2,
+}
+
+// ParseAPIVersion parses a version as returned by app/webapiVersion, e.g. "2.11.2"
+func ParseAPIVersion(version string) (APIVersion, error) {
+	var v APIVersion
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v, fmt.Errorf("invalid api version %q", version)
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid api version %q", version)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// String formats the version as major.minor.patch
+func (v APIVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when v is older than, equal to or newer than other
+func (v APIVersion) Compare(other APIVersion) int {
+	switch {
+	case v.Major != other.Major:
+		return compareInts(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return compareInts(v.Minor, other.Minor)
+	default:
+		return compareInts(v.Patch, other.Patch)
+	}
+}
+
+// AtLeast reports whether v is the same as or newer than other
+func (v APIVersion) AtLeast(other APIVersion) bool {
+	return v.Compare(other) >= 0
+}
+
+// compareInts returns -1, 0 or 1 when a is less than, equal to or greater than b
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// APIVersion returns the parsed Web API version of the qbittorrent client.
+// It is queried once and cached for the lifetime of the client
+func (c *Client) APIVersion() (APIVersion, error) {
+	return c.APIVersionCtx(context.Background())
+}
+
+// APIVersionCtx is APIVersion with a context that controls the lifetime of the request
+func (c *Client) APIVersionCtx(ctx context.Context) (APIVersion, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.apiVersion != nil {
+		return *c.apiVersion, nil
+	}
+
+	raw, err := c.WebAPIVersionCtx(ctx)
+	if err != nil {
+		return APIVersion{}, err
+	}
+	version, err := ParseAPIVersion(raw)
+	if err != nil {
+		return version, err
+	}
+	c.apiVersion = &version
+	return version, nil
+}
+
+// SupportsEndpoint reports whether the connected qbittorrent provides an endpoint,
+// given relative to api/v2/ such as "torrents/renameFolder"
+func (c *Client) SupportsEndpoint(ctx context.Context, endpoint string) (bool, error) {
+	since, ok := endpointVersions[apiBase+endpoint]
+	if !ok {
+		return true, nil
+	}
+	version, err := c.APIVersionCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version.AtLeast(since), nil
+}
+
+// requireEndpoint returns ErrUnsupported when the server is older than the endpoint
+func (c *Client) requireEndpoint(ctx context.Context, endpoint string) error {
+	since, ok := endpointVersions[endpoint]
+	if !ok {
+		return nil
+	}
+	version, err := c.APIVersionCtx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to determine api version: %w", err)
+	}
+	if !version.AtLeast(since) {
+		return fmt.Errorf("%s requires web api %s but the server has %s: %w",
+			strings.TrimPrefix(endpoint, apiBase), since, version, ErrUnsupported)
+	}
+	return nil
+}
+
+// versionedEndpoint picks current when the server is at least since and legacy otherwise,
+// for endpoints that qbittorrent renamed
+func (c *Client) versionedEndpoint(ctx context.Context, legacy string, current string, since APIVersion) (string, error) {
+	version, err := c.APIVersionCtx(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine api version: %w", err)
+	}
+	if version.AtLeast(since) {
+		return current, nil
+	}
+	return legacy, nil
+}
@@ -0,0 +1,95 @@
+package qbt_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+)
+
+// versionServer reports a Web API version and counts the requests it gets
+type versionServer struct {
+	version string
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (s *versionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/api/v2/")
+	s.mu.Lock()
+	s.requests[endpoint]++
+	s.mu.Unlock()
+
+	if endpoint == "app/webapiVersion" {
+		io.WriteString(w, s.version)
+		return
+	}
+	io.WriteString(w, "Ok.")
+}
+
+// count returns the number of requests sent to endpoint
+func (s *versionServer) count(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// newVersionClient returns a client for a server with the given Web API version
+func newVersionClient(t *testing.T, version string) (*qbt.Client, *versionServer) {
+	server := &versionServer{version: version, requests: map[string]int{}}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+	return qbt.NewClient(srv.URL), server
+}
+
+func TestPauseAndResumeByVersion(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		version string
+		pause   string
+		resume  string
+	}{
+		{"2.8.3", "torrents/pause", "torrents/resume"},
+		{"2.11.2", "torrents/stop", "torrents/start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			client, server := newVersionClient(t, tt.version)
+
+			if err := client.Pause([]string{hash}); err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Resume([]string{hash}); err != nil {
+				t.Fatal(err)
+			}
+			if n := server.count(tt.pause); n != 1 {
+				t.Errorf("sent %s %d times, want 1", tt.pause, n)
+			}
+			if n := server.count(tt.resume); n != 1 {
+				t.Errorf("sent %s %d times, want 1", tt.resume, n)
+			}
+			if n := server.count("app/webapiVersion"); n != 1 {
+				t.Errorf("asked for the version %d times, want it cached after the first", n)
+			}
+		})
+	}
+}
+
+func TestUnsupportedEndpoint(t *testing.T) {
+	client, server := newVersionClient(t, "2.0.0")
+
+	// tags arrived with 2.3.0
+	if _, err := client.CreateTags([]string{"linux"}); !errors.Is(err, qbt.ErrUnsupported) {
+		t.Fatalf("got %v, want ErrUnsupported", err)
+	}
+	if n := server.count("torrents/createTags"); n != 0 {
+		t.Errorf("sent torrents/createTags %d times to a server without it", n)
+	}
+}
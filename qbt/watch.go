@@ -0,0 +1,241 @@
+package qbt
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRefreshInterval is the poll interval used until qbittorrent reports its own
+const defaultRefreshInterval = 1500 * time.Millisecond
+
+// Event is a change to a torrent detected by a Watcher or PeerWatcher
+type Event interface {
+	// TorrentHash is the hash of the torrent the event is about
+	TorrentHash() string
+}
+
+// TorrentAdded is emitted for a torrent that was not there on the previous poll
+type TorrentAdded struct {
+	Torrent TorrentInfo
+}
+
+// TorrentRemoved is emitted for a torrent that is gone, with its last known state
+type TorrentRemoved struct {
+	Torrent TorrentInfo
+}
+
+// TorrentCompleted is emitted when a torrent finishes downloading
+type TorrentCompleted struct {
+	Torrent TorrentInfo
+}
+
+// StateChanged is emitted when the state of a torrent changes, e.g. from downloading to stoppedDL
+type StateChanged struct {
+	Torrent TorrentInfo
+	From    string
+	To      string
+}
+
+// CategoryChanged is emitted when a torrent is moved to another category
+type CategoryChanged struct {
+	Torrent TorrentInfo
+	From    string
+	To      string
+}
+
+// TagsChanged is emitted when tags are added to or removed from a torrent
+type TagsChanged struct {
+	Torrent TorrentInfo
+	From    []string
+	To      []string
+}
+
+// TrackerChanged is emitted when the tracker a torrent is working with changes
+type TrackerChanged struct {
+	Torrent TorrentInfo
+	From    string
+	To      string
+}
+
+func (e TorrentAdded) TorrentHash() string     { return e.Torrent.Hash }
+func (e TorrentRemoved) TorrentHash() string   { return e.Torrent.Hash }
+func (e TorrentCompleted) TorrentHash() string { return e.Torrent.Hash }
+func (e StateChanged) TorrentHash() string     { return e.Torrent.Hash }
+func (e CategoryChanged) TorrentHash() string  { return e.Torrent.Hash }
+func (e TagsChanged) TorrentHash() string      { return e.Torrent.Hash }
+func (e TrackerChanged) TorrentHash() string   { return e.Torrent.Hash }
+
+// Watcher polls sync/maindata and emits an Event for every change to a torrent
+type Watcher struct {
+	// Interval between polls. Zero uses the refresh_interval qbittorrent reports
+	Interval time.Duration
+	// EmitExisting emits TorrentAdded for the torrents present on the first poll
+	// instead of silently taking them as the starting point
+	EmitExisting bool
+
+	state *SyncState
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewWatcher returns a Watcher reading from a Client or a fake of it
+func NewWatcher(reader SyncReader) *Watcher {
+	return &Watcher{state: NewSyncState(reader)}
+}
+
+// Run polls until ctx is done or a request fails, calling handler for every event
+// in the order they were detected. It returns ctx.Err() when ctx is done
+func (w *Watcher) Run(ctx context.Context, handler func(Event)) error {
+	previous, err := w.state.UpdateCtx(ctx)
+	if err != nil {
+		return err
+	}
+	if w.EmitExisting {
+		for _, event := range diffSnapshots(SyncSnapshot{}, previous) {
+			handler(event)
+		}
+	}
+
+	timer := time.NewTimer(w.interval(previous))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		current, err := w.state.UpdateCtx(ctx)
+		if err != nil {
+			return err
+		}
+		for _, event := range diffSnapshots(previous, current) {
+			handler(event)
+		}
+		previous = current
+		timer.Reset(w.interval(current))
+	}
+}
+
+// Events runs the watcher in the background and returns a channel receiving its events.
+// The channel is closed when ctx is done or a request fails; Err reports why
+func (w *Watcher) Events(ctx context.Context) <-chan Event {
+	return streamEvents(ctx, w.Run, func(err error) {
+		w.mu.Lock()
+		w.err = err
+		w.mu.Unlock()
+	})
+}
+
+// Err returns the error that closed the channel returned by Events
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// interval returns the time to wait before the next poll
+func (w *Watcher) interval(snapshot SyncSnapshot) time.Duration {
+	if w.Interval > 0 {
+		return w.Interval
+	}
+	if snapshot.ServerState.RefreshInterval > 0 {
+		return time.Duration(snapshot.ServerState.RefreshInterval) * time.Millisecond
+	}
+	return defaultRefreshInterval
+}
+
+// streamEvents calls run in the background, sending the events it emits to the returned
+// channel. The channel is closed once run returns, after passing its error to done
+func streamEvents(ctx context.Context, run func(context.Context, func(Event)) error, done func(error)) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		err := run(ctx, func(event Event) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+		done(err)
+	}()
+	return events
+}
+
+// diffSnapshots returns the events that lead from previous to current, ordered by hash
+func diffSnapshots(previous SyncSnapshot, current SyncSnapshot) []Event {
+	var events []Event
+
+	for _, hash := range sortedHashes(current.Torrents) {
+		torrent := current.Torrents[hash]
+		old, ok := previous.Torrents[hash]
+		if !ok {
+			events = append(events, TorrentAdded{Torrent: torrent})
+			continue
+		}
+
+		if old.State != torrent.State {
+			events = append(events, StateChanged{Torrent: torrent, From: old.State, To: torrent.State})
+		}
+		if old.Progress < 1 && torrent.Progress >= 1 {
+			events = append(events, TorrentCompleted{Torrent: torrent})
+		}
+		if old.Category != torrent.Category {
+			events = append(events, CategoryChanged{Torrent: torrent, From: old.Category, To: torrent.Category})
+		}
+		if from, to := splitTags(old.Tags), splitTags(torrent.Tags); !equalStrings(from, to) {
+			events = append(events, TagsChanged{Torrent: torrent, From: from, To: to})
+		}
+		if old.Tracker != torrent.Tracker {
+			events = append(events, TrackerChanged{Torrent: torrent, From: old.Tracker, To: torrent.Tracker})
+		}
+	}
+
+	for _, hash := range sortedHashes(previous.Torrents) {
+		if _, ok := current.Torrents[hash]; !ok {
+			events = append(events, TorrentRemoved{Torrent: previous.Torrents[hash]})
+		}
+	}
+	return events
+}
+
+// sortedHashes returns the keys of torrents in a stable order
+func sortedHashes(torrents map[string]TorrentInfo) []string {
+	hashes := make([]string, 0, len(torrents))
+	for hash := range torrents {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// splitTags splits the comma separated tags of a torrent into a sorted list
+func splitTags(tags string) []string {
+	var list []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			list = append(list, tag)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// equalStrings reports whether two lists hold the same strings in the same order
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package qbt_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/superturkey650/go-qbittorrent/qbt"
+	"github.com/superturkey650/go-qbittorrent/qbt/qbttest"
+)
+
+// receive returns the next n events of ch, failing the test if they take too long
+func receive(t *testing.T, ch <-chan qbt.Event, n int) []qbt.Event {
+	t.Helper()
+	var events []qbt.Event
+	timeout := time.After(5 * time.Second)
+	for len(events) < n {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				t.Fatalf("events closed after %v", events)
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatalf("got %v, want %d events", events, n)
+		}
+	}
+	return events
+}
+
+func TestWatcherEvents(t *testing.T) {
+	srv := qbttest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	srv.AddTorrent(qbt.TorrentInfo{Hash: hashA, Name: "a", Size: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := qbt.NewWatcher(client)
+	watcher.Interval = 10 * time.Millisecond
+	watcher.EmitExisting = true
+	events := watcher.Events(ctx)
+
+	if added, ok := receive(t, events, 1)[0].(qbt.TorrentAdded); !ok || added.Torrent.Hash != hashA {
+		t.Fatalf("first event is %#v, want a being added", added)
+	}
+
+	srv.CompleteTorrent(hashA)
+	if err := client.CreateCategory("done", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetTorrentCategory([]string{hashA}, "done"); err != nil {
+		t.Fatal(err)
+	}
+	got := receive(t, events, 3)
+	var kinds []string
+	for _, event := range got {
+		switch e := event.(type) {
+		case qbt.StateChanged:
+			kinds = append(kinds, "state "+e.From+" -> "+e.To)
+		case qbt.TorrentCompleted:
+			kinds = append(kinds, "completed")
+		case qbt.CategoryChanged:
+			kinds = append(kinds, "category "+e.From+" -> "+e.To)
+		default:
+			kinds = append(kinds, "unexpected")
+		}
+	}
+	want := []string{"state downloading -> uploading", "completed", "category  -> done"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("got events %q, want %q", kinds, want)
+	}
+
+	if err := client.Delete([]string{hashA}, false); err != nil {
+		t.Fatal(err)
+	}
+	if removed, ok := receive(t, events, 1)[0].(qbt.TorrentRemoved); !ok || removed.Torrent.Name != "a" {
+		t.Errorf("last event is %#v, want a being removed", removed)
+	}
+
+	cancel()
+	for range events {
+	}
+	if err := watcher.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("watcher stopped with %v, want context.Canceled", err)
+	}
+}